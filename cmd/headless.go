@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/meteormin/minder/commands"
+	"github.com/meteormin/minder/vfs"
+)
+
+// 창 없이 명령 실행: --script, exec, repl. fyne 앱/창은 만들지 않는다.
+
+// headlessScrollback save-log용으로 남겨 두는 줄 수
+const headlessScrollback = 10000
+
+// headlessOptions exec/repl/--script 공통 옵션
+type headlessOptions struct {
+	dir string
+	yes bool
+	no  bool
+}
+
+func (o *headlessOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.dir, "C", "", "run in `dir` instead of the current directory")
+	fs.BoolVar(&o.yes, "yes", false, "answer yes to every confirmation (overwrite, delete)")
+	fs.BoolVar(&o.no, "no", false, "answer no to every confirmation")
+}
+
+// prompter --yes/--no가 없으면 stdin에서 묻는다 (질문은 stderr)
+func (o *headlessOptions) prompter(in *bufio.Reader) commands.Prompter {
+	if o.yes || o.no {
+		return commands.AutoPrompter{Answer: o.yes, Out: os.Stderr}
+	}
+	return commands.TerminalPrompter{In: in, Out: os.Stderr}
+}
+
+// context 창 없는 명령 Context. 출력은 바로 stdout으로
+func (o *headlessOptions) context(in *bufio.Reader) (*commands.Context, *streamOutput, error) {
+	if o.yes && o.no {
+		return nil, nil, errors.New("--yes and --no are mutually exclusive")
+	}
+	dir := o.dir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, nil, err
+		}
+		dir = wd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return nil, nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	out := newStreamOutput(os.Stdout)
+	ctx := &commands.Context{
+		Logger:     logger,
+		Pwd:        commands.NewString(dir),
+		Env:        commands.NewEnv(os.Environ()),
+		Dirs:       &commands.DirStack{},
+		ConsoleBuf: out,
+		Prompter:   o.prompter(in),
+		FS:         vfs.NewMux(vfs.NewArchives(vfs.OS)),
+	}
+	if isTerminal(os.Stderr) {
+		ctx.Progress = reportProgress
+	}
+	return ctx, out, nil
+}
+
+// reportProgress 복사 진행 상황을 stderr 한 줄에 덮어 쓴다
+func reportProgress(t commands.Transfer) {
+	if t.End {
+		fmt.Fprint(os.Stderr, "\r\x1b[K")
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\x1b[K"+t.String())
+}
+
+// headlessLogger 로그는 파일에만 (stdout은 명령 출력)
+func headlessLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(logFile, nil))
+}
+
+// reportErr 명령 오류를 stderr로. exit는 오류가 아니다.
+func reportErr(err error) {
+	if err == nil || errors.Is(err, commands.ErrExit) {
+		return
+	}
+	msg := err.Error()
+	if isTerminal(os.Stderr) {
+		msg = commands.ErrorText(msg)
+	}
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+func newHeadlessFlags(name, usage string, opts *headlessOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	opts.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: "+usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// runExec minder exec [-C dir] [--yes|--no] <command>: 인자를 이어 한 줄로 실행
+func runExec(args []string) int {
+	logger = headlessLogger()
+
+	var opts headlessOptions
+	fs := newHeadlessFlags("exec", `minder exec [-C dir] [--yes|--no] "<command>"`, &opts)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return commands.StatusOK
+		}
+		return commands.StatusUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return commands.StatusUsage
+	}
+
+	c, out, err := opts.context(bufio.NewReader(os.Stdin))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "minder exec:", err)
+		return commands.StatusUsage
+	}
+
+	// Ctrl+C로 명령 취소
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = commands.Call(sigCtx, c, strings.Join(fs.Args(), " "))
+	out.endLine()
+	reportErr(err)
+	return commands.ExitCode(err)
+}
+
+// runRepl minder repl: 한 줄씩 읽어 실행. Ctrl+C는 실행 중인 명령만 취소, Ctrl+D로 끝
+func runRepl(args []string) int {
+	logger = headlessLogger()
+
+	var opts headlessOptions
+	fs := newHeadlessFlags("repl", "minder repl [-C dir] [--yes|--no]", &opts)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return commands.StatusOK
+		}
+		return commands.StatusUsage
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return commands.StatusUsage
+	}
+
+	// 명령 줄과 확인 답을 같은 reader에서 읽는다
+	in := bufio.NewReader(os.Stdin)
+	c, out, err := opts.context(in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "minder repl:", err)
+		return commands.StatusUsage
+	}
+
+	var (
+		mu     sync.Mutex
+		cancel context.CancelFunc
+	)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+	go func() {
+		for range sig {
+			mu.Lock()
+			if cancel != nil {
+				cancel()
+			}
+			mu.Unlock()
+		}
+	}()
+	run := func(f func(ctx context.Context) error) error {
+		ctx, cn := context.WithCancel(context.Background())
+		mu.Lock()
+		cancel = cn
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			cancel = nil
+			mu.Unlock()
+			cn()
+		}()
+		return f(ctx)
+	}
+
+	// 대화형이므로 ~/.minderrc 실행
+	err = run(func(ctx context.Context) error {
+		_, rcErr := commands.RunRc(ctx, c)
+		return rcErr
+	})
+	out.endLine()
+	reportErr(err)
+
+	interactive := isTerminal(os.Stdin)
+	status := commands.StatusOK
+	for {
+		if interactive {
+			fmt.Print(c.Prompt() + " ")
+		}
+		line, readErr := in.ReadString('\n')
+		if line == "" && readErr != nil {
+			if interactive {
+				fmt.Println()
+			}
+			return status
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		err = run(func(ctx context.Context) error {
+			return commands.Call(ctx, c, line)
+		})
+		out.endLine()
+		if errors.Is(err, commands.ErrExit) {
+			return status
+		}
+		reportErr(err)
+		status = commands.ExitCode(err)
+	}
+}
+
+// runScript --script <file>: 파일의 명령을 차례로, 실패하면 멈춘다
+func runScript(file string, opts headlessOptions) int {
+	logger = headlessLogger()
+
+	f, err := os.Open(file)
+	if err != nil {
+		logger.Error("failed open script", "file", file, "err", err)
+		fmt.Fprintln(os.Stderr, err)
+		return commands.StatusFailure
+	}
+	defer func(f *os.File) {
+		fErr := f.Close()
+		if fErr != nil {
+			logger.Error("failed close script", "err", fErr)
+		}
+	}(f)
+
+	c, out, err := opts.context(bufio.NewReader(os.Stdin))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "minder:", err)
+		return commands.StatusUsage
+	}
+
+	// Ctrl+C로 스크립트 중단
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = commands.RunScript(sigCtx, c, f, file)
+	out.endLine()
+	reportErr(err)
+	return commands.ExitCode(err)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// streamOutput 명령 출력을 바로 w로 쓴다. 터미널이 아니면 색을 빼고, 표는 TSV로.
+// 내용은 Buffer에도 남겨 save-log가 쓸 수 있게 한다.
+type streamOutput struct {
+	commands.Buffer
+
+	mu          sync.Mutex
+	w           io.Writer
+	color       bool
+	atLineStart bool
+}
+
+func newStreamOutput(f *os.File) *streamOutput {
+	o := &streamOutput{w: f, color: isTerminal(f), atLineStart: true}
+	o.MaxLines = headlessScrollback
+	return o
+}
+
+func (o *streamOutput) Write(p []byte) (int, error) {
+	return o.WriteString(string(p))
+}
+
+func (o *streamOutput) WriteString(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, _ = o.Buffer.WriteString(s)
+	if err := o.emit(s); err != nil {
+		return 0, err
+	}
+	o.atLineStart = strings.HasSuffix(s, "\n")
+	return len(s), nil
+}
+
+func (o *streamOutput) WriteByte(ch byte) error {
+	_, err := o.WriteString(string(ch))
+	return err
+}
+
+func (o *streamOutput) WriteTable(t *commands.Table) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_ = o.Buffer.WriteTable(t)
+	var sb strings.Builder
+	if !o.atLineStart {
+		sb.WriteString("\n")
+	}
+	if err := t.WriteTSV(&sb); err != nil {
+		return err
+	}
+	o.atLineStart = true
+	return o.emit(sb.String())
+}
+
+// Reset clear: 터미널이면 화면도 지운다
+func (o *streamOutput) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.Buffer.Reset()
+	if o.color {
+		_, _ = io.WriteString(o.w, "\x1b[H\x1b[2J")
+	}
+	o.atLineStart = true
+}
+
+// endLine 명령 출력이 줄 중간에서 끝났으면 줄바꿈
+func (o *streamOutput) endLine() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.atLineStart {
+		o.Buffer.EndLine()
+		_ = o.emit("\n")
+		o.atLineStart = true
+	}
+}
+
+func (o *streamOutput) emit(s string) error {
+	if !o.color {
+		s = commands.StripANSI(s)
+	}
+	_, err := io.WriteString(o.w, s)
+	return err
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -8,11 +10,14 @@ import (
 
 	"fyne.io/fyne/v2"
 	"github.com/meteormin/minder"
+	"github.com/meteormin/minder/commands"
 	"github.com/meteormin/minder/components"
+	"github.com/meteormin/minder/vfs"
 )
 
 var (
 	logPath = "/var/log/minder.log"
+	logFile *os.File
 	logger  *slog.Logger
 )
 
@@ -26,15 +31,40 @@ func init() {
 		}
 	}
 
+	logFile = f
 	w := io.MultiWriter(os.Stdout, f)
 	logger = slog.New(slog.NewTextHandler(w, nil))
 }
 
 func main() {
-	var basePath string
+	// 창 없이 실행하는 하위 명령
 	if len(os.Args) > 1 {
-		logger.Info("argument", "arg[1]", os.Args[1])
-		basePath = os.Args[1]
+		switch os.Args[1] {
+		case "exec":
+			os.Exit(runExec(os.Args[2:]))
+		case "repl":
+			os.Exit(runRepl(os.Args[2:]))
+		}
+	}
+
+	script := flag.String("script", "", "run minder commands from `file` and exit")
+	scrollback := flag.Int("scrollback", 0, "keep at most `n` lines in the console (0: default, -1: unlimited)")
+	var scriptOpts headlessOptions
+	flag.BoolVar(&scriptOpts.yes, "yes", false, "with --script: answer yes to every confirmation")
+	flag.BoolVar(&scriptOpts.no, "no", false, "with --script: answer no to every confirmation")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintln(out, "usage: minder [flags] [path]")
+		fmt.Fprintln(out, `       minder exec [-C dir] [--yes|--no] "<command>"`)
+		fmt.Fprintln(out, "       minder repl [-C dir] [--yes|--no]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var basePath string
+	if flag.NArg() > 0 {
+		logger.Info("argument", "arg[0]", flag.Arg(0))
+		basePath = flag.Arg(0)
 		if basePath == "" {
 			wd, err := os.Getwd()
 			if err != nil {
@@ -49,6 +79,12 @@ func main() {
 		panic(err)
 	}
 
+	// --script: 창 없이 스크립트만 실행하고 종료
+	if *script != "" {
+		scriptOpts.dir = absPath
+		os.Exit(runScript(*script, scriptOpts))
+	}
+
 	c, err := minder.New(minder.Config{
 		Logger:     logger,
 		BasePath:   absPath,
@@ -59,12 +95,24 @@ func main() {
 		panic(err)
 	}
 
+	// 트리, 미리보기, 터미널이 같은 파일 시스템을 본다. 원격 저장소는 connect로 /@name에 붙는다.
+	// zip, tar 파일 안은 읽기 전용 디렉터리로 보인다.
+	fsys := vfs.NewMux(vfs.NewArchives(vfs.OS))
+
+	// 사이드바와 미리보기는 다시 그릴 때마다 새로 만든다. 이전 것의 감시(fsnotify)를 닫는다
+	var pf *components.Pathfinder
+	var preview *components.Preview
+
 	c.Layout().SetSideBar(func() fyne.CanvasObject {
-		pf := components.NewPathfinder(components.PathfinderConfig{
+		if pf != nil {
+			pf.Destroy()
+		}
+		pf = components.NewPathfinder(components.PathfinderConfig{
 			FileTreeConfig: components.FileTreeConfig{
 				Window:     c.Window(),
 				RootDir:    c.Store().Pathfinder.CurrentDir,
 				ShowHidden: c.Store().Pathfinder.ShowHidden,
+				FS:         fsys,
 				OnSelected: func(uid string) {
 					setErr := c.Store().PreviewPath.Set(uid)
 					if setErr != nil {
@@ -72,6 +120,14 @@ func main() {
 						return
 					}
 				},
+				OnDirOpen: func(uid string) {
+					// 파일 기록은 UI 스레드 밖에서
+					go func() {
+						if recErr := commands.RecordDir(uid); recErr != nil {
+							c.Logger().Error("failed record dir", "dir", uid, "err", recErr)
+						}
+					}()
+				},
 			},
 			Logger: c.Logger(),
 		})
@@ -79,20 +135,29 @@ func main() {
 	})
 
 	c.Layout().SetMainFrame(func() fyne.CanvasObject {
-		preview := components.NewPreview(components.PreviewConfig{
+		if preview != nil {
+			preview.PreviewPane.Destroy()
+		}
+		preview = components.NewPreview(components.PreviewConfig{
 			Logger: c.Logger(),
 			Path:   c.Store().PreviewPath,
+			FS:     fsys,
 		})
 		return preview.PreviewPane.Root()
 	})
 
 	c.Layout().SetBottom(func() fyne.CanvasObject {
 		term := components.NewTerminal(components.TerminalConfig{
+			Context:        c.Context(),
 			Logger:         c.Logger(),
 			Window:         c.Window(),
 			Pwd:            c.Store().Pathfinder.CurrentDir,
+			Selected:       c.Store().PreviewPath,
 			Input:          c.Store().Terminal.Input,
 			RefreshSideBar: c.Layout().RenderSideBar,
+			Scrollback:     *scrollback,
+			FS:             fsys,
+			ShowHidden:     c.Store().Pathfinder.ShowHidden,
 		})
 		return term.Container
 	})
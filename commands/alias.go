@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const aliasFile = "aliases"
+
+var (
+	cmdAlias = Cmd{
+		Name: "alias",
+		Args: []string{"[name[=value]...]"},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			return handleAlias(c, args)
+		},
+	}
+
+	cmdUnalias = Cmd{
+		Name: "unalias",
+		Args: []string{"[-a]", "<name>..."},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			return handleUnalias(c, args)
+		},
+	}
+
+	aliases = &aliasStore{}
+)
+
+// aliasStore 설정 디렉터리의 aliases 파일에 "name=value" 한 줄씩 저장
+type aliasStore struct {
+	mu     sync.Mutex
+	loaded bool
+	m      map[string]string
+}
+
+func (s *aliasStore) load() error {
+	if s.loaded {
+		return nil
+	}
+	s.m = map[string]string{}
+
+	fp, err := configPath(aliasFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fp)
+	if errors.Is(err, os.ErrNotExist) {
+		s.loaded = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if name, value, ok := strings.Cut(sc.Text(), "="); ok && name != "" {
+			s.m[name] = value
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return err
+	}
+	s.loaded = true
+	return nil
+}
+
+func (s *aliasStore) save() error {
+	fp, err := configPath(aliasFile)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, name := range s.names() {
+		sb.WriteString(name + "=" + s.m[name] + "\n")
+	}
+	return os.WriteFile(fp, []byte(sb.String()), 0o644)
+}
+
+func (s *aliasStore) names() []string {
+	names := make([]string, 0, len(s.m))
+	for name := range s.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *aliasStore) get(name string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return "", false, err
+	}
+	v, ok := s.m[name]
+	return v, ok, nil
+}
+
+// checkCycle name에서 시작해 첫 단어를 따라가며 순환이 있는지 본다.
+// 자기 자신으로 시작하는 별칭(alias ls='ls -l')은 허용한다.
+func (s *aliasStore) checkCycle(name string) error {
+	chain := []string{name}
+	cur := name
+	for {
+		v, ok := s.m[cur]
+		if !ok {
+			return nil
+		}
+		next := firstWord(v)
+		if next == "" || next == cur {
+			return nil
+		}
+		for _, seen := range chain {
+			if seen == next {
+				return fmt.Errorf("alias: recursive alias: %s", strings.Join(append(chain, next), " -> "))
+			}
+		}
+		chain = append(chain, next)
+		cur = next
+	}
+}
+
+func firstWord(s string) string {
+	words, err := tokenize(s, func(string) (string, bool) { return "", false })
+	if err != nil || len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
+
+// expandAlias 명령 이름이 별칭이면 펼친다. 순환은 오류로 보고한다.
+// literal은 words와 나란히 펼친 결과에 맞춰 돌려준다.
+func expandAlias(c *Context, words []string, literal []bool) ([]string, []bool, error) {
+	var chain []string
+	for len(words) > 0 {
+		name := words[0]
+		v, ok, err := aliases.get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return words, literal, nil
+		}
+		for i, seen := range chain {
+			if seen != name {
+				continue
+			}
+			if i == len(chain)-1 {
+				// 직전 별칭이 자기 이름으로 시작: 실제 명령으로 본다
+				return words, literal, nil
+			}
+			return nil, nil, fmt.Errorf("alias: recursive alias: %s", strings.Join(append(chain, name), " -> "))
+		}
+		chain = append(chain, name)
+
+		expanded, quoted, err := tokenizeLiteral(v, c.lookupVar)
+		if err != nil {
+			return nil, nil, fmt.Errorf("alias: %s: %w", name, err)
+		}
+		var rest []bool
+		if len(literal) > 1 {
+			rest = literal[1:]
+		}
+		words = append(expanded, words[1:]...)
+		literal = append(quoted, rest...)
+	}
+	return words, literal, nil
+}
+
+// quoteAlias 다시 입력할 수 있는 형태로 작은따옴표 감싸기
+func quoteAlias(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
+func handleAlias(c *Context, args []string) error {
+	aliases.mu.Lock()
+	defer aliases.mu.Unlock()
+	if err := aliases.load(); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		var lines []string
+		for _, name := range aliases.names() {
+			lines = append(lines, "alias "+name+"="+quoteAlias(aliases.m[name]))
+		}
+		_, err := c.ConsoleBuf.WriteString(strings.Join(lines, "\n"))
+		return err
+	}
+
+	var lines []string
+	var errs []error
+	changed := false
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			v, found := aliases.m[name]
+			if !found {
+				errs = append(errs, fmt.Errorf("alias: %s: not found", name))
+				continue
+			}
+			lines = append(lines, "alias "+name+"="+quoteAlias(v))
+			continue
+		}
+		if name == "" || strings.ContainsAny(name, " \t/=$'\"") {
+			errs = append(errs, fmt.Errorf("alias: `%s': invalid alias name", name))
+			continue
+		}
+
+		prev, had := aliases.m[name]
+		aliases.m[name] = value
+		if err := aliases.checkCycle(name); err != nil {
+			// 순환이면 되돌림
+			if had {
+				aliases.m[name] = prev
+			} else {
+				delete(aliases.m, name)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		changed = true
+	}
+
+	if changed {
+		if err := aliases.save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(lines) > 0 {
+		if _, err := c.ConsoleBuf.WriteString(strings.Join(lines, "\n")); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func handleUnalias(c *Context, args []string) error {
+	opts, names, err := getopt("unalias", args, "a")
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 && !opts.has('a') {
+		return usageError("unalias: missing argument")
+	}
+
+	aliases.mu.Lock()
+	defer aliases.mu.Unlock()
+	if err = aliases.load(); err != nil {
+		return err
+	}
+
+	if opts.has('a') {
+		aliases.m = map[string]string{}
+		return aliases.save()
+	}
+
+	var errs []error
+	for _, name := range names {
+		if _, ok := aliases.m[name]; !ok {
+			errs = append(errs, fmt.Errorf("unalias: %s: not found", name))
+			continue
+		}
+		delete(aliases.m, name)
+	}
+	if err = aliases.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// SGR 색상/스타일. 터미널(TextGrid)이 셀 스타일로 그린다.
+const (
+	sgrReset     = "\x1b[0m"
+	sgrBold      = "1"
+	sgrUnderline = "4"
+	sgrRed       = "31"
+	sgrGreen     = "32"
+	sgrYellow    = "33"
+	sgrBlue      = "34"
+	sgrCyan      = "36"
+	sgrGray      = "90"
+)
+
+// ansiSeq CSI 시퀀스 전체 (SGR 외 커서 이동 등도 포함)
+var ansiSeq = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// StripANSI 파일 리다이렉트, json/tsv 변환 등 글자만 필요한 곳에서 이스케이프 제거
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiSeq.ReplaceAllString(s, "")
+}
+
+// paint NO_COLOR가 설정돼 있으면 그대로
+func paint(s string, codes ...string) string {
+	if s == "" || os.Getenv("NO_COLOR") != "" {
+		return s
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + s + sgrReset
+}
+
+// ErrorText 오류 메시지 (빨강)
+func ErrorText(s string) string { return paint(s, sgrBold, sgrRed) }
+
+func warnText(s string) string { return paint(s, sgrYellow) }
+
+func okText(s string) string { return paint(s, sgrGreen) }
+
+// pathText 파일 경로. 디렉터리면 dirText
+func (c *Context) pathText(p string) string {
+	if vfs.IsDir(c.fsys(), p) {
+		return dirText(p)
+	}
+	return paint(p, sgrCyan)
+}
+
+func dirText(p string) string { return paint(p, sgrBold, sgrBlue) }
+
+// pathsText 공백으로 이은 경로 목록
+func (c *Context) pathsText(ps []string) string {
+	out := make([]string, len(ps))
+	for i, p := range ps {
+		out[i] = c.pathText(p)
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,673 @@
+package commands
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+var (
+	cmdZip = Cmd{
+		Name: "zip",
+		Args: []string{"[--exclude <pattern>]...", "<archive.zip>", "<src>..."},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			return handlePack(ctx, c, "zip", args)
+		},
+	}
+
+	cmdTar = Cmd{
+		Name: "tar",
+		Args: []string{"[--exclude <pattern>]...", "<archive.tar|.tar.gz>", "<src>..."},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			return handlePack(ctx, c, "tar", args)
+		},
+	}
+
+	cmdUnzip = Cmd{
+		Name: "unzip",
+		Args: []string{"[-d <dir>]", "<archive.zip>..."},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			return handleUnpack(ctx, c, "unzip", args)
+		},
+	}
+
+	cmdUntar = Cmd{
+		Name: "untar",
+		Args: []string{"[-d <dir>]", "<archive.tar|.tar.gz|.tar.bz2>..."},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			return handleUnpack(ctx, c, "untar", args)
+		},
+	}
+)
+
+// packFormat 만들 수 있는 형식인지. bzip2는 읽기만 한다
+func packFormat(name, archive string) (string, error) {
+	format := vfs.ArchiveFormat(archive)
+	switch {
+	case name == "zip" && format == "zip":
+	case name == "tar" && (format == "tar" || format == "tar.gz"):
+	case name == "tar" && format == "tar.bz2":
+		return "", fmt.Errorf("tar: cannot create bzip2 archives (read only): %s", archive)
+	case name == "zip":
+		return "", usageError("zip: archive name must end in .zip: %s", archive)
+	default:
+		return "", usageError("tar: archive name must end in .tar, .tar.gz or .tgz: %s", archive)
+	}
+	return format, nil
+}
+
+// unpackFormat 명령이 풀 수 있는 형식인지
+func unpackFormat(name, archive string) (string, error) {
+	format := vfs.ArchiveFormat(archive)
+	if name == "unzip" && format == "zip" || name == "untar" && strings.HasPrefix(format, "tar") {
+		return format, nil
+	}
+	if name == "unzip" {
+		return "", fmt.Errorf("unzip: not a zip archive: %s", archive)
+	}
+	return "", fmt.Errorf("untar: not a tar archive: %s", archive)
+}
+
+// packItem 아카이브에 넣을 항목 하나
+type packItem struct {
+	path string // FS 경로
+	name string // 아카이브 안 이름 ('/' 구분)
+	info fs.FileInfo
+}
+
+// packer zip, tar 만들기. 숨김 파일 설정과 --exclude를 따른다
+type packer struct {
+	c        *Context
+	archive  string
+	excludes []string
+	hidden   bool // 숨김 파일 포함
+	files    int
+	skipped  int // 일반 파일도 디렉터리도 아니라 뺀 항목 (링크 등)
+}
+
+// excluded --exclude 패턴: '/'가 있으면 아카이브 안 경로의 뒷부분("sub/*.log"), 없으면 이름과 비교
+func (p *packer) excluded(name string) (bool, error) {
+	for _, pat := range p.excludes {
+		targets := []string{path.Base(name)}
+		if strings.Contains(pat, "/") {
+			targets = targets[:0]
+			for rest := name; ; {
+				targets = append(targets, rest)
+				i := strings.IndexByte(rest, '/')
+				if i < 0 {
+					break
+				}
+				rest = rest[i+1:]
+			}
+		}
+		for _, target := range targets {
+			ok, err := matchSegment(pat, target)
+			if err != nil {
+				return false, fmt.Errorf("bad exclude pattern %q: %w", pat, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// collect 소스들을 훑어 넣을 항목과 전체 크기를 모은다.
+// "aDir/."은 aDir 안의 항목만, 나머지는 소스 이름부터 넣는다.
+func (p *packer) collect(ctx context.Context, srcs []string) ([]packItem, int64, error) {
+	fsys := p.c.fsys()
+	var items []packItem
+	var total int64
+	for _, src := range srcs {
+		root, contents := asDotContents(src)
+		if !contents {
+			root = src
+		}
+		base := filepath.Dir(root)
+		if contents {
+			base = root
+		}
+		err := vfs.WalkDir(fsys, root, func(fp string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(base, fp)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+			skip := fp == p.archive || fp != root && !p.hidden && strings.HasPrefix(d.Name(), ".")
+			name := filepath.ToSlash(rel)
+			if !skip {
+				if skip, err = p.excluded(name); err != nil {
+					return err
+				}
+			}
+			if skip {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() && !d.Type().IsRegular() {
+				p.skipped++
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				p.files++
+				total += info.Size()
+			}
+			items = append(items, packItem{path: fp, name: name, info: info})
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return items, total, nil
+}
+
+// write 아카이브 파일을 쓴다. 실패하거나 취소되면 만들던 파일을 지운다
+func (p *packer) write(ctx context.Context, format string, items []packItem, total int64) error {
+	fsys := p.c.fsys()
+	if err := fsys.MkdirAll(filepath.Dir(p.archive), 0o755); err != nil {
+		return err
+	}
+	out, err := fsys.OpenFile(p.archive, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	k := p.c.newTracker(p.archive, p.archive, total)
+	if format == "zip" {
+		err = p.writeZip(ctx, out, items, k)
+	} else {
+		err = p.writeTar(ctx, out, format, items, k)
+	}
+	k.end()
+	if outErr := out.Close(); err == nil {
+		err = outErr
+	}
+	if err != nil {
+		_ = fsys.Remove(p.archive)
+	}
+	return err
+}
+
+func (p *packer) writeZip(ctx context.Context, w io.Writer, items []packItem, k *tracker) error {
+	zw := zip.NewWriter(w)
+	for _, it := range items {
+		hdr, err := zip.FileInfoHeader(it.info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = it.name
+		if it.info.IsDir() {
+			hdr.Name += "/"
+			hdr.Method = zip.Store
+		} else {
+			hdr.Method = zip.Deflate
+		}
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if !it.info.IsDir() {
+			if err := p.copyInto(ctx, fw, it.path, k); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+func (p *packer) writeTar(ctx context.Context, w io.Writer, format string, items []packItem, k *tracker) error {
+	var gz *gzip.Writer
+	if format == "tar.gz" {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for _, it := range items {
+		hdr, err := tar.FileInfoHeader(it.info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = it.name
+		if it.info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !it.info.IsDir() {
+			if err := p.copyInto(ctx, tw, it.path, k); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+func (p *packer) copyInto(ctx context.Context, w io.Writer, src string, k *tracker) error {
+	in, err := p.c.fsys().Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	_, err = io.Copy(w, k.reader(ctxReader{ctx: ctx, r: in}))
+	return err
+}
+
+func handlePack(ctx context.Context, c *Context, name string, args []string) error {
+	excludes, operands, literal, err := longOpt(name, args, c.argLiteral(args), "exclude")
+	if err != nil {
+		return err
+	}
+	if operands, literal, err = expandBraceArgs(operands, literal); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(operands) < 2 {
+		return usageError("%s: missing argument", name)
+	}
+	absArc, err := resolvePath(c, operands[0])
+	if err != nil {
+		return err
+	}
+	format, err := packFormat(name, absArc)
+	if err != nil {
+		return err
+	}
+
+	absSrcs := make([]string, 0, len(operands)-1)
+	for _, src := range operands[1:] {
+		absSrc, err := resolveOperand(c, src)
+		if err != nil {
+			return err
+		}
+		absSrcs = append(absSrcs, absSrc)
+	}
+	srcs, misses := expandSources(ctx, c.fsys(), absSrcs, literal[1:])
+	if len(misses) > 0 {
+		errs := make([]error, len(misses))
+		for i, m := range misses {
+			errs[i] = fmt.Errorf("%s: %s: %w", name, m.pattern, m.err)
+		}
+		return errors.Join(errs...)
+	}
+
+	p := &packer{c: c, archive: absArc, excludes: excludes, hidden: c.showHidden()}
+	items, total, err := p.collect(ctx, srcs)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("%s: nothing to archive", name)
+	}
+
+	if c.exists(absArc) {
+		act, err := resolveConflict(ctx, c, absArc)
+		if err != nil {
+			return err
+		}
+		if act == "skip" {
+			_, err = fmt.Fprintf(c.ConsoleBuf, "%s: skipped %s", name, c.pathText(absArc))
+			return err
+		}
+	}
+
+	err = p.write(ctx, format, items, total)
+	c.refreshSideBar()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.ConsoleBuf, "%s: %s to %s%s", name, c.pathsText(srcs), c.pathText(absArc), countNote(p.files, 0, p.skipped))
+	return err
+}
+
+// countNote " (3 files, 1 skipped)"
+func countNote(n, skipped, unsupported int) string {
+	note := fmt.Sprintf(" (%d files", n)
+	if skipped > 0 {
+		note += fmt.Sprintf(", %d skipped", skipped)
+	}
+	if unsupported > 0 {
+		note += fmt.Sprintf(", %d not regular files", unsupported)
+	}
+	return note + ")"
+}
+
+// unpacker zip, tar 풀기. 항목은 dest 밖으로 나갈 수 없다 (zip-slip)
+type unpacker struct {
+	c    *Context
+	dest string
+
+	files       int
+	skipped     int // 충돌에서 건너뜀
+	unsupported int // 링크, 장치 파일 등
+}
+
+// target 항목 이름 → dest 아래 경로. 절대 경로나 ".."로 밖을 가리키면 false
+func (u *unpacker) target(entry string) (string, bool) {
+	name, ok := vfs.CleanEntryName(entry)
+	if !ok {
+		return "", false
+	}
+	if name == "" {
+		return u.dest, true
+	}
+	p := filepath.Join(u.dest, filepath.FromSlash(name))
+	return p, isSubpath(p, u.dest)
+}
+
+// dir 디렉터리 항목
+func (u *unpacker) dir(target string) error {
+	return u.c.fsys().MkdirAll(target, 0o755)
+}
+
+// file 파일 항목: cp와 같은 충돌 처리 후 r의 내용을 쓴다
+func (u *unpacker) file(ctx context.Context, target string, r io.Reader, perm fs.FileMode, modTime time.Time) error {
+	fsys := u.c.fsys()
+	if u.c.exists(target) {
+		act, err := resolveConflict(ctx, u.c, target)
+		if err != nil {
+			return err
+		}
+		if act == "skip" {
+			u.skipped++
+			return nil
+		}
+		if err := fsys.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+	if err := fsys.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = 0o644
+	}
+	out, err := fsys.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, ctxReader{ctx: ctx, r: r})
+	if outErr := out.Close(); err == nil {
+		err = outErr
+	}
+	if err != nil {
+		_ = fsys.Remove(target)
+		return err
+	}
+	if !modTime.IsZero() {
+		_ = fsys.Chtimes(target, modTime, modTime)
+	}
+	u.files++
+	return nil
+}
+
+// unzip 안전하지 않은 항목은 건너뛰고 오류로 모은다
+func (u *unpacker) unzip(ctx context.Context, arc string) error {
+	zr, closer, err := openZip(ctx, u.c, arc)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closer.Close() }()
+
+	var total int64
+	for _, zf := range zr.File {
+		total += int64(zf.UncompressedSize64)
+	}
+	k := u.c.newTracker(arc, u.dest, total)
+	defer k.end()
+
+	var errs []error
+	for _, zf := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		target, ok := u.target(zf.Name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s: path escapes destination, skipped", arc, zf.Name))
+			continue
+		}
+		mode := zf.Mode()
+		switch {
+		case strings.HasSuffix(zf.Name, "/") || mode.IsDir():
+			err = u.dir(target)
+		case mode.IsRegular():
+			err = u.zipFile(ctx, zf, target, k)
+		default:
+			u.unsupported++
+			continue
+		}
+		if err != nil {
+			if isCanceled(err) {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (u *unpacker) zipFile(ctx context.Context, zf *zip.File, target string, k *tracker) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return u.file(ctx, target, k.reader(rc), zf.Mode().Perm(), zf.Modified)
+}
+
+// untar 진행 상황은 압축된 아카이브를 읽은 양으로 센다
+func (u *unpacker) untar(ctx context.Context, arc, format string) error {
+	fsys := u.c.fsys()
+	f, err := fsys.Open(arc)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	total := int64(-1)
+	if fi, err := f.Stat(); err == nil {
+		total = fi.Size()
+	}
+	k := u.c.newTracker(arc, u.dest, total)
+	defer k.end()
+
+	tr, err := vfs.OpenTar(k.reader(ctxReader{ctx: ctx, r: f}), format)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// 밖을 가리키는 이름은 아래에서 직접 거른다
+		if err != nil && !errors.Is(err, tar.ErrInsecurePath) {
+			return err
+		}
+		target, ok := u.target(hdr.Name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s: path escapes destination, skipped", arc, hdr.Name))
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = u.dir(target)
+		case tar.TypeReg:
+			err = u.file(ctx, target, tr, hdr.FileInfo().Mode().Perm(), hdr.ModTime)
+		default:
+			u.unsupported++
+			continue
+		}
+		if err != nil {
+			if isCanceled(err) {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// openZip zip은 임의 접근이 필요하다. ReaderAt가 없는 FS(원격)는 임시 파일로 받아서 연다
+func openZip(ctx context.Context, c *Context, arc string) (*zip.Reader, io.Closer, error) {
+	f, err := c.fsys().Open(arc)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	if ra, ok := f.(io.ReaderAt); ok {
+		zr, err := newZipReader(ra, fi.Size())
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		return zr, f, nil
+	}
+
+	defer func() { _ = f.Close() }()
+	tmp, err := os.CreateTemp("", "minder-*.zip")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := tempFile{tmp}
+	r, end := c.trackTransfer(ctxReader{ctx: ctx, r: f}, arc, tmp.Name(), fi.Size())
+	n, err := io.Copy(tmp, r)
+	end()
+	if err != nil {
+		_ = cleanup.Close()
+		return nil, nil, err
+	}
+	zr, err := newZipReader(tmp, n)
+	if err != nil {
+		_ = cleanup.Close()
+		return nil, nil, err
+	}
+	return zr, cleanup, nil
+}
+
+// newZipReader 밖을 가리키는 항목이 있어도 연다 (unpacker.target이 거른다)
+func newZipReader(r io.ReaderAt, size int64) (*zip.Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if errors.Is(err, zip.ErrInsecurePath) {
+		err = nil
+	}
+	return zr, err
+}
+
+// tempFile 닫으면 지워지는 임시 파일
+type tempFile struct{ *os.File }
+
+func (t tempFile) Close() error {
+	err := t.File.Close()
+	_ = os.Remove(t.Name())
+	return err
+}
+
+func handleUnpack(ctx context.Context, c *Context, name string, args []string) error {
+	opts, operands, err := getopt(name, args, "d:")
+	if err != nil {
+		return err
+	}
+	var literal []bool
+	if operands, literal, err = expandBraceArgs(operands, c.argLiteral(operands)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(operands) == 0 {
+		return usageError("%s: missing argument", name)
+	}
+
+	var dest string
+	if opts.has('d') {
+		dest, err = resolvePath(c, opts.get('d'))
+	} else {
+		dest, err = baseDir(c)
+	}
+	if err != nil {
+		return err
+	}
+
+	absArcs := make([]string, 0, len(operands))
+	for _, op := range operands {
+		absArc, err := resolveOperand(c, op)
+		if err != nil {
+			return err
+		}
+		absArcs = append(absArcs, absArc)
+	}
+	arcs, misses := expandSources(ctx, c.fsys(), absArcs, literal)
+	var errs []error
+	for _, m := range misses {
+		errs = append(errs, fmt.Errorf("%s: %s: %w", name, m.pattern, m.err))
+	}
+	if len(arcs) > 0 {
+		if err := c.fsys().MkdirAll(dest, 0o755); err != nil {
+			return err
+		}
+	}
+
+	u := &unpacker{c: c, dest: dest}
+	var done []string
+	for _, arc := range arcs {
+		format, err := unpackFormat(name, arc)
+		if err == nil {
+			if format == "zip" {
+				err = u.unzip(ctx, arc)
+			} else {
+				err = u.untar(ctx, arc, format)
+			}
+		}
+		if isCanceled(err) {
+			c.refreshSideBar()
+			return err
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		done = append(done, arc)
+	}
+	c.refreshSideBar()
+
+	if len(done) > 0 {
+		if _, err := fmt.Fprintf(c.ConsoleBuf, "%s: %s to %s%s", name, c.pathsText(done), c.pathText(dest), countNote(u.files, u.skipped, u.unsupported)); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
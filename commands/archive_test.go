@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/data/binding"
+	"github.com/meteormin/minder/vfs"
+)
+
+// archiveEntry 테스트 아카이브 항목. 이름이 '/'로 끝나면 디렉터리
+type archiveEntry struct{ name, data string }
+
+func zipBytes(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = io.WriteString(w, e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.data)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(e.name, "/") {
+			hdr.Mode, hdr.Typeflag = 0o755, tar.TypeDir
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// bzip2Tar "b/c.txt"(bzip), "../evil.txt"(evil)이 든 tar.bz2. Go에는 bzip2 쓰기가 없다
+const bzip2Tar = "QlpoOTFBWSZTWYxInAUAAIr7gMmAABBAAf+AAiB6JF9QCCggAHQSUp6mnqGagGj2poE3qCUUAeoNAA0APuBggHqAD40RBBzty0YFKRiUQQUBUfx1rWPokCkQBDNEREak1eUZSOUjgH4o+EIfx9VobDjn02HJjdxINxdyRThQkIxInAU="
+
+// evilEntries dest 밖을 가리키는 항목들과 정상 항목 하나
+var evilEntries = []archiveEntry{
+	{name: "../x", data: "up"},
+	{name: "/abs", data: "abs"},
+	{name: "a/../../y", data: "inner"},
+	{name: "ok/f.txt", data: "ok"},
+}
+
+// 밖을 가리키는 항목은 건너뛰고 오류로 알리며, dest 밖에는 아무것도 쓰지 않는다
+func TestUnpackZipSlip(t *testing.T) {
+	bz, err := base64.StdEncoding.DecodeString(bzip2Tar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		archive string
+		data    []byte
+		line    string
+		escapes []string // 오류에 나와야 하는 항목 이름
+		want    map[string]string
+	}{
+		{
+			name:    "zip",
+			archive: "/work/evil.zip",
+			data:    zipBytes(t, evilEntries),
+			line:    "unzip -d out evil.zip",
+			escapes: []string{"../x", "/abs", "a/../../y"},
+			want:    map[string]string{"/work/out/ok/f.txt": "ok"},
+		},
+		{
+			name:    "tar",
+			archive: "/work/evil.tar",
+			data:    tarBytes(t, evilEntries),
+			line:    "untar -d out evil.tar",
+			escapes: []string{"../x", "/abs", "a/../../y"},
+			want:    map[string]string{"/work/out/ok/f.txt": "ok"},
+		},
+		{
+			name:    "tar.bz2",
+			archive: "/work/evil.tar.bz2",
+			data:    bz,
+			line:    "untar -d out evil.tar.bz2",
+			escapes: []string{"../evil.txt"},
+			want:    map[string]string{"/work/out/b/c.txt": "bzip"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := vfs.NewMem()
+			if err := fsys.MkdirAll("/work", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := vfs.WriteFile(fsys, tt.archive, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			c, _ := newTestContext(fsys, "/work")
+			err := run(c, tt.line)
+			for _, name := range tt.escapes {
+				if err == nil || !strings.Contains(err.Error(), name+": path escapes destination") {
+					t.Errorf("err = %v, want %s reported", err, name)
+				}
+			}
+			checkFiles(t, fsys, tt.want)
+
+			// dest(/work/out) 밖에는 처음 아카이브만 있어야 한다
+			err = vfs.WalkDir(fsys, "/", func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && p != tt.archive && !strings.HasPrefix(p, "/work/out/") {
+					t.Errorf("%s: written outside destination", p)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// 이미 있는 파일은 cp처럼 물어본다
+func TestUnpackConflict(t *testing.T) {
+	arc := zipBytes(t, []archiveEntry{{name: "a.txt", data: "new a"}, {name: "b.txt", data: "new b"}})
+	tests := []struct {
+		name    string
+		answers []string
+		want    map[string]string
+	}{
+		{
+			name:    "overwrite and skip",
+			answers: []string{"overwrite", "skip"},
+			want:    map[string]string{"/work/a.txt": "new a", "/work/b.txt": "old b"},
+		},
+		{
+			name:    "skip all",
+			answers: []string{"skip", "skip"},
+			want:    map[string]string{"/work/a.txt": "old a", "/work/b.txt": "old b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := vfs.NewMem()
+			writeFiles(t, fsys, map[string]string{"/work/a.txt": "old a", "/work/b.txt": "old b"})
+			if err := vfs.WriteFile(fsys, "/work/x.zip", arc, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			c, p := newTestContext(fsys, "/work", tt.answers...)
+			if err := run(c, "unzip x.zip"); err != nil {
+				t.Fatal(err)
+			}
+			if len(p.Asked) != 2 {
+				t.Errorf("asked %v, want 2 questions", p.Asked)
+			}
+			checkFiles(t, fsys, tt.want)
+		})
+	}
+}
+
+// zipNames 아카이브 안 항목 이름들
+func zipNames(t *testing.T, fsys vfs.FS, p string) []string {
+	t.Helper()
+	data, err := vfs.ReadFile(fsys, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, zf := range zr.File {
+		names = append(names, zf.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// --exclude와 숨김 파일 설정. 소스로 직접 준 숨김 디렉터리는 넣는다
+func TestPackFilters(t *testing.T) {
+	files := map[string]string{
+		"/work/src/a.txt":       "a",
+		"/work/src/b.log":       "b",
+		"/work/src/.env":        "env",
+		"/work/src/sub/c.tmp":   "c",
+		"/work/src/other/c.tmp": "c",
+		"/work/.dot/d.txt":      "d",
+	}
+	tests := []struct {
+		name   string
+		line   string
+		hidden bool
+		want   []string
+	}{
+		{
+			name:   "all",
+			line:   "zip out.zip src",
+			hidden: true,
+			want:   []string{"src/", "src/.env", "src/a.txt", "src/b.log", "src/other/", "src/other/c.tmp", "src/sub/", "src/sub/c.tmp"},
+		},
+		{
+			name:   "exclude",
+			line:   "zip --exclude '*.log' --exclude sub/*.tmp out.zip src",
+			hidden: true,
+			want:   []string{"src/", "src/.env", "src/a.txt", "src/other/", "src/other/c.tmp", "src/sub/"},
+		},
+		{
+			name: "hidden off",
+			line: "zip out.zip src .dot",
+			want: []string{".dot/", ".dot/d.txt", "src/", "src/a.txt", "src/b.log", "src/other/", "src/other/c.tmp", "src/sub/", "src/sub/c.tmp"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := vfs.NewMem()
+			writeFiles(t, fsys, files)
+			c, _ := newTestContext(fsys, "/work")
+			hidden := tt.hidden
+			c.ShowHidden = binding.BindBool(&hidden)
+			if err := run(c, tt.line); err != nil {
+				t.Fatal(err)
+			}
+			if got := zipNames(t, fsys, "/work/out.zip"); !slices.Equal(got, tt.want) {
+				t.Errorf("entries = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
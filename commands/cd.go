@@ -1,34 +1,98 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+
+	"github.com/meteormin/minder/vfs"
 )
 
+const varOldPwd = "OLDPWD"
+
 var cmdCd = Cmd{
 	Name: "cd",
-	Args: []string{"<dst>"},
-	Exec: func(c *Context, args []string) error {
+	Args: []string{"[<dst> | -]"},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		if len(args) > 1 {
+			return usageError("cd: too many arguments")
+		}
 		if len(args) == 0 {
-			return errors.New("cd: missing argument")
+			// 인자 없으면 홈으로
+			return handleChangeDirectory(ctx, c, "~", false)
+		}
+		if args[0] == "-" {
+			return handleChangeBack(ctx, c)
 		}
-		return handleChangeDirectory(c, args[0])
+		return handleChangeDirectory(ctx, c, args[0], c.argLiteral(args)[0])
 	},
 }
 
-func handleChangeDirectory(c *Context, dst string) error {
-	fp, err := pathToAbs(c, dst)
+// handleChangeDirectory literal이면 dst를 글롭 확장하지 않는다 (따옴표로 감싼 인자, 기록된 경로)
+func handleChangeDirectory(ctx context.Context, c *Context, dst string, literal bool) error {
+	fp, err := resolveDir(ctx, c, dst, literal)
 	if err != nil {
 		return err
 	}
 
-	err = c.Pwd.Set(fp)
-	if err != nil {
+	if err = changeDir(c, fp); err != nil {
 		return err
 	}
 
-	c.RefreshSideBar()
-
-	_, err = fmt.Fprintf(c.ConsoleBuf, "cd: %s", fp)
+	_, err = fmt.Fprintf(c.ConsoleBuf, "cd: %s", dirText(fp))
 	return err
 }
+
+// handleChangeBack cd -: 직전 디렉터리로
+func handleChangeBack(ctx context.Context, c *Context) error {
+	old, ok := c.lookupVar(varOldPwd)
+	if !ok || old == "" {
+		return errors.New("cd: OLDPWD not set")
+	}
+	return handleChangeDirectory(ctx, c, old, true)
+}
+
+// resolveDir cd 대상 해석: 글롭은 하나로만 매치돼야 하고 디렉터리여야 한다
+func resolveDir(ctx context.Context, c *Context, dst string, literal bool) (string, error) {
+	fp, err := resolvePath(c, dst)
+	if err != nil {
+		return "", err
+	}
+	if hasGlob(fp) && !literal {
+		matches, err := expandPattern(ctx, c.fsys(), fp)
+		if err != nil {
+			return "", err
+		}
+		if len(matches) > 1 {
+			return "", fmt.Errorf("cd: ambiguous directory %q", dst)
+		}
+		fp = matches[0]
+	}
+
+	fi, err := c.fsys().Stat(fp)
+	if err != nil {
+		return "", err
+	}
+	// 아카이브 안도 읽기 전용 디렉터리로 들어갈 수 있다
+	if !fi.IsDir() && !vfs.IsArchiveDir(c.fsys(), fp) {
+		return "", fmt.Errorf("cd: not a directory: %s", dst)
+	}
+	return fp, nil
+}
+
+// changeDir Pwd 변경 + OLDPWD 기록. cd, pushd, popd 공통
+func changeDir(c *Context, fp string) error {
+	prev, _ := c.Pwd.Get()
+	if err := c.Pwd.Set(fp); err != nil {
+		return err
+	}
+	if c.Env != nil && prev != "" && prev != fp {
+		c.Env.Set(varOldPwd, prev)
+	}
+	if err := RecordDir(fp); err != nil {
+		c.Logger.Error("failed record dir", "dir", fp, "err", err)
+	}
+
+	c.refreshSideBar()
+	return nil
+}
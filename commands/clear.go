@@ -1,9 +1,11 @@
 package commands
 
+import "context"
+
 var cmdClear = Cmd{
 	Name:  "clear",
 	Usage: "clear",
-	Exec: func(c *Context, args []string) error {
+	Exec: func(_ context.Context, c *Context, args []string) error {
 		return handleClear(c)
 	},
 }
@@ -1,15 +1,19 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
 	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/data/binding"
+	"github.com/meteormin/minder/vfs"
 )
 
 const historyFile = ".minder_history"
@@ -18,21 +22,41 @@ var (
 	mu sync.Mutex
 
 	commands = map[string]Cmd{
-		cmdCd.Name:      cmdCd,
-		cmdClear.Name:   cmdClear,
-		cmdTouch.Name:   cmdTouch,
-		cmdMkdir.Name:   cmdMkdir,
-		cmdCopy.Name:    cmdCopy,
-		cmdMove.Name:    cmdMove,
-		cmdRm.Name:      cmdRm,
-		cmdHistory.Name: cmdHistory,
-		cmdExit.Name:    cmdExit,
+		cmdCd.Name:         cmdCd,
+		cmdClear.Name:      cmdClear,
+		cmdTouch.Name:      cmdTouch,
+		cmdMkdir.Name:      cmdMkdir,
+		cmdCopy.Name:       cmdCopy,
+		cmdMove.Name:       cmdMove,
+		cmdRm.Name:         cmdRm,
+		cmdHistory.Name:    cmdHistory,
+		cmdRetry.Name:      cmdRetry,
+		cmdExport.Name:     cmdExport,
+		cmdUnset.Name:      cmdUnset,
+		cmdEnv.Name:        cmdEnv,
+		cmdAlias.Name:      cmdAlias,
+		cmdUnalias.Name:    cmdUnalias,
+		cmdPushd.Name:      cmdPushd,
+		cmdPopd.Name:       cmdPopd,
+		cmdDirs.Name:       cmdDirs,
+		cmdZ.Name:          cmdZ,
+		cmdLs.Name:         cmdLs,
+		cmdJSON.Name:       cmdJSON,
+		cmdTSV.Name:        cmdTSV,
+		cmdSaveLog.Name:    cmdSaveLog,
+		cmdConnect.Name:    cmdConnect,
+		cmdDisconnect.Name: cmdDisconnect,
+		cmdZip.Name:        cmdZip,
+		cmdUnzip.Name:      cmdUnzip,
+		cmdTar.Name:        cmdTar,
+		cmdUntar.Name:      cmdUntar,
+		cmdExit.Name:       cmdExit,
 	}
 
 	cmdHelp = Cmd{
 		Name:  "help",
 		Usage: "help",
-		Exec: func(c *Context, _ []string) error {
+		Exec: func(_ context.Context, c *Context, _ []string) error {
 			return help(c)
 		},
 	}
@@ -40,7 +64,7 @@ var (
 	cmdExit = Cmd{
 		Name:  "exit",
 		Usage: "exit",
-		Exec: func(c *Context, _ []string) error {
+		Exec: func(_ context.Context, c *Context, _ []string) error {
 			return exit(c)
 		},
 	}
@@ -48,25 +72,67 @@ var (
 	cmdHistory = Cmd{
 		Name:  "history",
 		Usage: "history",
-		Exec: func(c *Context, _ []string) error {
+		Exec: func(_ context.Context, c *Context, _ []string) error {
 			return history(c)
 		},
 	}
 )
 
+// help는 commands를 나열하므로 초기화 순환을 피해 init에서 등록
+func init() {
+	commands[cmdHelp.Name] = cmdHelp
+}
+
 type Context struct {
 	Logger         *slog.Logger
 	Window         fyne.Window
 	Pwd            binding.String
-	ConsoleBuf     *strings.Builder
-	RefreshSideBar func()
+	Selected       binding.String // $SEL: 파일 트리에서 선택한 파일 (없으면 nil)
+	Env            *Env
+	Dirs           *DirStack
+	ConsoleBuf     Output
+	RefreshSideBar func()           // 없으면(헤드리스) 무시
+	Prompter       Prompter         // 덮어쓰기/삭제 확인. 없으면 Window의 다이얼로그
+	FS             vfs.FS           // 명령이 다루는 파일 시스템. 없으면 vfs.OS
+	Progress       func(t Transfer) // 파일 복사 진행 상황. 명령 고루틴에서 불린다
+	ShowHidden     binding.Bool     // 숨김 파일 표시 설정 (zip, tar). 없으면 모두 포함
+
+	stdin   *Buffer         // 파이프로 받은 앞 명령의 출력
+	ctx     context.Context // 실행 중인 명령의 ctx. fsys가 원격 요청에 넘긴다
+	literal []bool          // 실행 중인 명령의 인자(Exec의 args)마다 따옴표로 감싸 펼치지 않는지 (argLiteral)
+
+	report     *opReport // 이번 호출의 keep going 결과 (withReport)
+	lastReport *opReport // retry 대상
+	skipped    int       // 충돌에서 건너뛴 경로 수. report가 없어도 센다 (mv 폴백이 원본을 지울지 본다)
+
+	sourceDepth int // source 중첩 깊이
+}
+
+// fsys Context.FS, 없으면 로컬 디스크
+func (c *Context) fsys() vfs.FS {
+	if c.FS != nil {
+		return vfs.WithContext(c.ctx, c.FS)
+	}
+	return vfs.OS
+}
+
+// showHidden 숨김 파일을 포함할지. 설정이 없으면(헤드리스) 포함한다
+func (c *Context) showHidden() bool {
+	if c.ShowHidden == nil {
+		return true
+	}
+	show, err := c.ShowHidden.Get()
+	return err != nil || show
 }
 
+// ErrExit 창 없이 exit를 실행했을 때. 헤드리스 실행기가 받아서 종료한다.
+var ErrExit = errors.New("exit")
+
 type Cmd struct {
 	Name  string
 	Args  []string
 	Usage string
-	Exec  func(c *Context, args []string) error
+	Exec  func(ctx context.Context, c *Context, args []string) error
 }
 
 func (cmd Cmd) history(c *Context) {
@@ -112,8 +178,10 @@ func (cmd Cmd) history(c *Context) {
 	}
 }
 
-func parseArgs(cmd string) Cmd {
-	s := strings.Split(cmd, " ")
+func parseArgs(s []string) Cmd {
+	if len(s) == 0 {
+		return cmdHelp
+	}
 	c, ok := commands[s[0]]
 	if !ok {
 		return cmdHelp
@@ -147,6 +215,10 @@ func help(c *Context) error {
 }
 
 func exit(c *Context) error {
+	// 창 없이(--script, exec, repl) 실행 중이면 실행기에게 맡긴다
+	if c.Window == nil {
+		return ErrExit
+	}
 	c.Window.Close()
 	return nil
 }
@@ -174,8 +246,81 @@ func history(c *Context) error {
 	return err
 }
 
-func Call(c *Context, cmd string) error {
-	args := parseArgs(cmd)
+func Call(ctx context.Context, c *Context, cmd string) error {
+	p, err := splitPipeline(cmd)
+	if err == nil {
+		err = runPipeline(ctx, c, p)
+	}
+	c.setStatus(err)
+	return err
+}
+
+// callWords 파이프라인 한 단계 실행
+func callWords(ctx context.Context, c *Context, line string) error {
+	words, literal, err := tokenizeLiteral(line, c.lookupVar)
+	if err != nil {
+		return err
+	}
+	return execWords(ctx, c, words, literal)
+}
+
+// execWords 토큰화된 명령 실행 (timeout도 사용). literal은 words와 나란히, 글롭, 중괄호 확장에서 뺄 단어.
+// 명령의 panic은 스택을 로그에 남기고 ExitError로 돌려준다 (탭과 창은 살아 있다)
+func execWords(ctx context.Context, c *Context, words []string, literal []bool) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.Logger.Error("command panicked", "cmd", strings.Join(words, " "), "panic", r, "stack", string(debug.Stack()))
+			err = &ExitError{Code: StatusFailure, Err: fmt.Errorf("%s: panic: %v", words[0], r)}
+		}
+	}()
+
+	// 레지스트리 조회 전에 별칭 확장
+	words, literal, err = expandAlias(c, words, literal)
+	if err != nil {
+		return err
+	}
+
+	if len(words) > 0 {
+		if _, ok := commands[words[0]]; !ok {
+			return &ExitError{Code: StatusNotFound, Err: fmt.Errorf("%s: command not found", words[0])}
+		}
+	}
+	args := parseArgs(words)
 	args.history(c)
-	return args.Exec(c, args.Args)
+
+	// argLiteral가 뒤에서부터 맞추므로 인자 수만큼 채운다
+	var argLiteral []bool
+	if len(words) > 1 {
+		argLiteral = make([]bool, len(words)-1)
+		if len(literal) > 1 {
+			copy(argLiteral, literal[1:])
+		}
+	}
+	prev, prevLiteral := c.ctx, c.literal
+	c.ctx, c.literal = ctx, argLiteral
+	defer func() { c.ctx, c.literal = prev, prevLiteral }()
+	return args.Exec(ctx, c, args.Args)
+}
+
+// argLiteral args(Exec가 받은 인자의 뒷부분, getopt 피연산자)마다 따옴표로 감싸 펼치지 않는지
+func (c *Context) argLiteral(args []string) []bool {
+	lit := make([]bool, len(args))
+	if off := len(c.literal) - len(args); off >= 0 {
+		copy(lit, c.literal[off:])
+	}
+	return lit
+}
+
+func (c *Context) refreshSideBar() {
+	if c.RefreshSideBar != nil {
+		c.RefreshSideBar()
+	}
+}
+
+// setStatus $? 갱신 (ExitCode)
+func (c *Context) setStatus(err error) {
+	if c.Env == nil {
+		return
+	}
+	c.Env.setStatus(ExitCode(err))
 }
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// 명령의 panic은 ExitError가 되고 Context는 다음 명령에 그대로 쓸 수 있다
+func TestExecPanic(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	commands["boom"] = Cmd{Name: "boom", Exec: func(context.Context, *Context, []string) error {
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	}}
+	t.Cleanup(func() { delete(commands, "boom") })
+
+	fsys := vfs.NewMem()
+	if err := fsys.MkdirAll("/work", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newTestContext(fsys, "/work")
+	for _, line := range []string{"boom", "timeout 1 boom"} {
+		err := run(c, line)
+		if ExitCode(err) != StatusFailure || err == nil || !strings.Contains(err.Error(), "boom: panic: ") {
+			t.Errorf("%s: err = %v (status %d), want panic reported as failure", line, err, ExitCode(err))
+		}
+		if c.ctx != nil || c.literal != nil {
+			t.Errorf("%s: command state not restored", line)
+		}
+	}
+	if got := c.Env.Status(); got != StatusFailure {
+		t.Errorf("$? = %d, want %d", got, StatusFailure)
+	}
+	if err := run(c, "mkdir d"); err != nil || !vfs.Exists(fsys, "/work/d") {
+		t.Errorf("command after panic: %v", err)
+	}
+}
@@ -1,79 +1,101 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/widget"
+	"github.com/meteormin/minder/vfs"
 )
 
 var cmdCopy = Cmd{
 	Name: "cp",
-	Args: []string{"<src>", "<dst>"},
-	Exec: func(c *Context, args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("cp: missing argument")
+	Args: []string{"[-rk]", "<src>...", "<dst>"},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		opts, operands, err := getopt("cp", args, "rRk")
+		if err != nil {
+			return err
+		}
+		operands, literal, err := expandBraceArgs(operands, c.argLiteral(operands))
+		if err != nil {
+			return fmt.Errorf("cp: %w", err)
+		}
+		if len(operands) < 2 {
+			return usageError("cp: missing argument")
 		}
-		return handleCopy(c, args[0], args[1])
+		last := len(operands) - 1
+		return handleCopy(ctx, c, operands[:last], literal[:last], operands[last], opts.has('r') || opts.has('R'), opts.has('k'))
 	},
 }
 
-// copyEntry 패턴/".", 숨김 포함 여부까지 처리하는 엔트리 포인트
-func copyEntry(c *Context, srcPattern, dst string) error {
-	// 1) "aDir/." → 내용만 복사
-	if dir, ok := asDotContents(srcPattern); ok {
-		return copyDirContents(c, dir, dst)
-	}
-
-	// 2) 글롭 확장
-	srcs, err := expandPattern(srcPattern)
-	if err != nil {
+// copyEntries 소스 패턴들을 펼쳐 각각 dst로 복사한다.
+// 한 소스가 실패해도 나머지는 계속 처리하고 오류는 모아서 돌려준다. literal[i]이면 srcPatterns[i]는 글자 그대로.
+func copyEntries(ctx context.Context, c *Context, srcPatterns []string, literal []bool, dst string, recursive bool) error {
+	srcs, misses := expandSources(ctx, c.fsys(), srcPatterns, literal)
+	// 다중 소스면 목적지는 반드시 디렉터리여야
+	if err := checkMultiTarget(c.fsys(), len(srcs)+len(misses), dst); err != nil {
 		return err
 	}
-	// 3) 다중 소스면 목적지는 반드시 디렉터리여야
-	if len(srcs) > 1 {
-		if di, err := os.Stat(dst); err != nil || !di.IsDir() {
-			return fmt.Errorf("target %q is not a directory for multiple sources", dst)
+
+	var errs []error
+	for _, m := range misses {
+		err := c.report.fail(m.pattern, m.err, func(ctx context.Context, c *Context) error {
+			return copyEntries(ctx, c, []string{m.pattern}, nil, dst, recursive)
+		})
+		if err != nil {
+			errs = append(errs, err)
 		}
 	}
-	// 4) 각각 처리
 	for _, s := range srcs {
-		if dir, ok := asDotContents(s); ok {
-			// "aDir/." → 내용만 복사
-			if err = copyDirContents(c, dir, dst); err != nil {
-				return err
-			}
-			continue
-		}
-		if err = copyAny(c, s, dst); err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
+		err := c.report.fail(s, copyEntry(ctx, c, s, dst, recursive), func(ctx context.Context, c *Context) error {
+			return copyEntry(ctx, c, s, dst, recursive)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-func copyAny(c *Context, src, dst string) error {
+// copyEntry 펼쳐진 소스 하나를 복사. "aDir/."이면 내용만 복사
+func copyEntry(ctx context.Context, c *Context, src, dst string, recursive bool) error {
+	if dir, ok := asDotContents(src); ok {
+		if !recursive {
+			return fmt.Errorf("cp: -r not specified; omitting directory '%s'", dir)
+		}
+		return copyDirContents(ctx, c, dir, dst)
+	}
+	if !recursive {
+		if vfs.IsDir(c.fsys(), src) {
+			return fmt.Errorf("cp: -r not specified; omitting directory '%s'", src)
+		}
+	}
+	return copyAny(ctx, c, src, dst)
+}
+
+func copyAny(ctx context.Context, c *Context, src, dst string) error {
 	// dst가 디렉터리면 src 베이스 이름으로 붙임
-	if di, err := os.Stat(dst); err == nil && di.IsDir() {
+	if vfs.IsDir(c.fsys(), dst) {
 		dst = filepath.Join(dst, filepath.Base(src))
 	}
-	fi, err := os.Lstat(src)
+	fi, err := c.fsys().Lstat(src)
 	if err != nil {
 		return err
 	}
 	if fi.IsDir() {
-		return copyDir(c, src, dst)
+		return copyDir(ctx, c, src, dst)
 	}
-	return copyFile(c, src, dst)
+	return copyFile(ctx, c, src, dst)
 }
 
-func copyDir(c *Context, src, dst string) error {
+func copyDir(ctx context.Context, c *Context, src, dst string) error {
 	// 자기 하위로 복사 금지
 	srcAbs, _ := filepath.Abs(src)
 	dstAbs, _ := filepath.Abs(dst)
@@ -81,178 +103,219 @@ func copyDir(c *Context, src, dst string) error {
 		return fmt.Errorf("destination is inside source: %s -> %s", src, dst)
 	}
 
+	fsys := c.fsys()
 	// 최상위 대상이 존재 & 파일이면 충돌 처리
-	if st, err := os.Lstat(dst); err == nil && !st.IsDir() {
-		action, err := resolveConflict(c, dst)
+	if st, err := fsys.Lstat(dst); err == nil && !st.IsDir() {
+		action, err := resolveConflict(ctx, c, dst)
 		if err != nil {
 			return err
 		}
 		if action == "skip" {
+			c.skip(dst, "already exists")
 			return nil
 		}
-		if err := os.RemoveAll(dst); err != nil {
+		if err := fsys.RemoveAll(dst); err != nil {
 			return err
 		}
 	}
 
-	return filepath.WalkDir(src, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
+	return vfs.WalkDir(fsys, src, func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 		rel, _ := filepath.Rel(src, path)
 		target := filepath.Join(dst, rel)
+		retry := func(ctx context.Context, c *Context) error {
+			if fi, err := fsys.Lstat(path); err == nil && fi.IsDir() {
+				return copyDir(ctx, c, path, target)
+			}
+			return copyFile(ctx, c, path, target)
+		}
+
+		if walkErr != nil {
+			return c.report.fail(path, walkErr, retry)
+		}
 
-		info, err := os.Lstat(path)
+		info, err := fsys.Lstat(path)
 		if err != nil {
-			return err
+			return c.report.fail(path, err, retry)
 		}
 		if info.IsDir() {
-			return os.MkdirAll(target, 0o755)
-		}
-
-		// 파일: 충돌 확인
-		if exists(target) {
-			act, err := resolveConflict(c, target)
-			if err != nil {
-				return err
-			}
-			if act == "skip" {
-				return nil
-			}
-			if err := os.RemoveAll(target); err != nil {
-				return err
+			if err = fsys.MkdirAll(target, 0o755); err != nil {
+				if err = c.report.fail(path, err, retry); err != nil {
+					return err
+				}
+				// 대상 디렉터리가 없으니 하위는 건너뜀
+				return fs.SkipDir
 			}
+			return nil
 		}
-		return copyOneFile(c, path, target, info.Mode().Perm())
+		return copyLeaf(ctx, c, path, target, info.Mode().Perm())
 	})
 }
 
-func copyFile(c *Context, src, dst string) error {
+func copyFile(ctx context.Context, c *Context, src, dst string) error {
 	// 대상이 디렉터리라면 파일명 붙임
-	if di, err := os.Stat(dst); err == nil && di.IsDir() {
+	if vfs.IsDir(c.fsys(), dst) {
 		dst = filepath.Join(dst, filepath.Base(src))
 	}
+	srcFi, err := c.fsys().Stat(src)
+	if err != nil {
+		return err
+	}
+	return copyLeaf(ctx, c, src, dst, srcFi.Mode().Perm())
+}
+
+// copyLeaf 파일 하나: 충돌 처리 후 복사하고 결과를 report에 남긴다
+func copyLeaf(ctx context.Context, c *Context, src, dst string, perm fs.FileMode) error {
+	retry := func(ctx context.Context, c *Context) error { return copyLeaf(ctx, c, src, dst, perm) }
+
 	// 충돌 처리
-	if exists(dst) {
-		act, err := resolveConflict(c, dst)
+	if c.exists(dst) {
+		act, err := resolveConflict(ctx, c, dst)
 		if err != nil {
 			return err
 		}
 		if act == "skip" {
+			c.skip(dst, "already exists")
 			return nil
 		}
-		if err := os.RemoveAll(dst); err != nil {
-			return err
+		if err := c.fsys().RemoveAll(dst); err != nil {
+			return c.report.fail(src, err, retry)
 		}
 	}
 	// 부모 생성 후 복사
-	srcFi, err := os.Stat(src)
-	if err != nil {
-		return err
+	if err := copyOneFile(ctx, c, src, dst, perm); err != nil {
+		return c.report.fail(src, err, retry)
 	}
-	return copyOneFile(c, src, dst, srcFi.Mode().Perm())
+	c.report.succeed()
+	return nil
 }
 
-func copyOneFile(c *Context, src, dst string, perm fs.FileMode) error {
+func copyOneFile(ctx context.Context, c *Context, src, dst string, perm fs.FileMode) error {
 	logger := c.Logger
-	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+	fsys := c.fsys()
+	if err := fsys.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
 		return err
 	}
-	in, err := os.Open(src)
+	in, err := fsys.Open(src)
 	if err != nil {
 		return err
 	}
-	defer func(in *os.File) {
+	defer func(in vfs.File) {
 		inErr := in.Close()
 		if inErr != nil {
 			logger.Error("failed close file", "src", src)
 		}
 	}(in)
 
-	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	out, err := fsys.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
 	if err != nil {
 		return err
 	}
-	defer func(out *os.File) {
-		outErr := out.Close()
-		if outErr != nil {
-			logger.Error("failed close file", "dst", dst)
+	total := int64(-1)
+	if fi, err := in.Stat(); err == nil {
+		total = fi.Size()
+	}
+	r, end := c.trackTransfer(ctxReader{ctx: ctx, r: in}, src, dst, total)
+	_, err = io.Copy(out, r)
+	end()
+	// 원격 FS는 닫을 때 업로드가 끝나므로 Close 오류도 복사 실패
+	if outErr := out.Close(); outErr != nil {
+		logger.Error("failed close file", "dst", dst, "err", outErr)
+		if err == nil {
+			err = outErr
 		}
-	}(out)
-
-	_, err = io.Copy(out, in)
+	}
+	if isCanceled(err) {
+		// 중간에 취소된 파일은 남기지 않음
+		_ = fsys.Remove(dst)
+	}
 	return err
 }
 
-func resolveConflict(c *Context, dst string) (string, error) {
-	// 2-버튼 모달로 물어보기 (UI 스레드에서 생성)
-	ch := make(chan string, 1)
-	var dd dialog.Dialog
-
-	show := func() {
-		title := "already exists file"
-		msg := widget.NewLabel(fmt.Sprintf("already exists file:\n%s\noverwrite?", dst))
-		btnSkip := widget.NewButton("skip", func() { ch <- "skip"; dd.Hide() })
-		btnOverwrite := widget.NewButton("overwrite", func() { ch <- "overwrite"; dd.Hide() })
-
-		grid := container.NewGridWithColumns(2, btnSkip, btnOverwrite)
-		content := container.NewVBox(msg, widget.NewSeparator(), grid)
-
-		dd = dialog.NewCustomWithoutButtons(title, content, c.Window)
-		dd.Show()
+func resolveConflict(ctx context.Context, c *Context, dst string) (string, error) {
+	p, err := c.prompter()
+	if err != nil {
+		return "", err
 	}
-	// UI 스레드에서 다이얼로그 띄우기
-	fyne.Do(show)
-
-	// 백그라운드(현재 고루틴)에서 사용자 선택 대기
-	return <-ch, nil
+	overwrite, err := p.Confirm(ctx, Question{
+		Title:   "already exists file",
+		Message: fmt.Sprintf("already exists file:\n%s\noverwrite?", dst),
+		Yes:     "overwrite",
+		No:      "skip",
+	})
+	if err != nil {
+		return "", err
+	}
+	if overwrite {
+		return "overwrite", nil
+	}
+	return "skip", nil
 }
 
-func copyDirContents(c *Context, srcDir, dstDir string) error {
-	ents, err := os.ReadDir(srcDir)
+func copyDirContents(ctx context.Context, c *Context, srcDir, dstDir string) error {
+	fsys := c.fsys()
+	ents, err := fsys.ReadDir(srcDir)
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(dstDir, 0o755); err != nil {
+	if err := fsys.MkdirAll(dstDir, 0o755); err != nil {
 		return err
 	}
 
 	for _, e := range ents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		s := filepath.Join(srcDir, e.Name())
 		d := filepath.Join(dstDir, e.Name())
+		copyOne := copyFile
 		if e.IsDir() {
-			if err := copyDir(c, s, d); err != nil {
-				return err
-			}
-		} else {
-			if err := copyFile(c, s, d); err != nil {
-				return err
-			}
+			copyOne = copyDir
+		}
+		err := c.report.fail(s, copyOne(ctx, c, s, d), func(ctx context.Context, c *Context) error { return copyOne(ctx, c, s, d) })
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func handleCopy(c *Context, src string, dst string) error {
+func handleCopy(ctx context.Context, c *Context, srcs []string, literal []bool, dst string, recursive, keepGoing bool) error {
 	logger := c.Logger
-	absSrc, err := pathToAbs(c, src)
-	if err != nil {
-		logger.Error("failed path to abs", "src", src)
-		return err
+	absSrcs := make([]string, 0, len(srcs))
+	for _, src := range srcs {
+		absSrc, err := resolveOperand(c, src)
+		if err != nil {
+			logger.Error("failed resolve path", "src", src)
+			return err
+		}
+		absSrcs = append(absSrcs, absSrc)
 	}
-	absDst, err := pathToAbs(c, dst)
+	absDst, err := resolvePath(c, dst)
 	if err != nil {
-		logger.Error("failed path to abs", "dst", dst)
+		logger.Error("failed resolve path", "dst", dst)
 		return err
 	}
 
-	err = copyEntry(c, absSrc, absDst)
+	// -k: 실패한 경로는 기록만 하고 계속 진행
+	var rep *opReport
+	sub := c
+	if keepGoing {
+		rep = newReport("cp")
+		sub = c.withReport(rep)
+	}
+
+	err = copyEntries(ctx, sub, absSrcs, literal, absDst, recursive)
+	c.refreshSideBar()
 	if err != nil {
 		return err
 	}
+	if rep != nil {
+		return finishReport(c, rep)
+	}
 
-	c.RefreshSideBar()
-
-	_, err = fmt.Fprintf(c.ConsoleBuf, "cp: %s to %s", absSrc, absDst)
+	_, err = fmt.Fprintf(c.ConsoleBuf, "cp: %s to %s", c.pathsText(absSrcs), c.pathText(absDst))
 	return err
 }
@@ -0,0 +1,94 @@
+package commands
+
+import "testing"
+
+func TestCopy(t *testing.T) {
+	runFSCases(t, []fsCase{
+		{
+			name:  "file to dir",
+			files: map[string]string{"/work/a.txt": "a", "/dst/.keep": "k"},
+			line:  "cp a.txt /dst",
+			want:  map[string]string{"/dst/a.txt": "a", "/work/a.txt": "a"},
+		},
+		{
+			name:  "file rename",
+			files: map[string]string{"/work/a.txt": "a"},
+			line:  "cp a.txt b.txt",
+			want:  map[string]string{"/work/b.txt": "a"},
+		},
+		{
+			name:    "dir without -r",
+			files:   map[string]string{"/work/d/a.txt": "a"},
+			line:    "cp d /dst",
+			wantErr: true,
+			want:    map[string]string{"/dst/a.txt": "", "/dst/d/a.txt": ""},
+		},
+		{
+			name:  "recursive",
+			files: map[string]string{"/work/d/a.txt": "a", "/work/d/sub/b.txt": "b", "/dst/.keep": "k"},
+			line:  "cp -r d /dst",
+			want:  map[string]string{"/dst/d/a.txt": "a", "/dst/d/sub/b.txt": "b"},
+		},
+		{
+			name:  "recursive contents",
+			files: map[string]string{"/work/d/a.txt": "a", "/work/d/sub/b.txt": "b"},
+			line:  "cp -r d/. /dst",
+			want:  map[string]string{"/dst/a.txt": "a", "/dst/sub/b.txt": "b"},
+		},
+		{
+			name:  "glob",
+			files: map[string]string{"/work/a.txt": "a", "/work/b.txt": "b", "/work/c.log": "c", "/dst/.keep": "k"},
+			line:  "cp *.txt /dst",
+			want:  map[string]string{"/dst/a.txt": "a", "/dst/b.txt": "b", "/dst/c.log": ""},
+		},
+		{
+			name:    "conflict overwrite",
+			files:   map[string]string{"/work/a.txt": "new", "/dst/a.txt": "old"},
+			line:    "cp a.txt /dst",
+			answers: []string{"overwrite"},
+			want:    map[string]string{"/dst/a.txt": "new"},
+		},
+		{
+			name:    "conflict skip",
+			files:   map[string]string{"/work/a.txt": "new", "/dst/a.txt": "old"},
+			line:    "cp a.txt /dst",
+			answers: []string{"skip"},
+			want:    map[string]string{"/dst/a.txt": "old"},
+		},
+		{
+			name:    "conflict inside recursive copy",
+			files:   map[string]string{"/work/d/a.txt": "new a", "/work/d/b.txt": "b", "/dst/d/a.txt": "old a"},
+			line:    "cp -r d /dst",
+			answers: []string{"skip"},
+			want:    map[string]string{"/dst/d/a.txt": "old a", "/dst/d/b.txt": "b"},
+		},
+		{
+			name:    "no answer left",
+			files:   map[string]string{"/work/a.txt": "new", "/dst/a.txt": "old"},
+			line:    "cp a.txt /dst",
+			wantErr: true,
+			want:    map[string]string{"/dst/a.txt": "old"},
+		},
+		{
+			name:    "missing source",
+			files:   map[string]string{"/work/a.txt": "a", "/dst/.keep": "k"},
+			line:    "cp a.txt nope.txt /dst",
+			wantErr: true,
+			want:    map[string]string{"/dst/a.txt": "a"},
+		},
+		{
+			name:    "keep going",
+			files:   map[string]string{"/work/a.txt": "a", "/work/d/x.txt": "x", "/work/b.txt": "b", "/dst/.keep": "k"},
+			line:    "cp -k a.txt d b.txt /dst",
+			wantErr: true, // 요약 뒤에 실패가 있었다고 알린다
+			want:    map[string]string{"/dst/a.txt": "a", "/dst/b.txt": "b", "/dst/d/x.txt": ""},
+		},
+		{
+			name:    "into itself",
+			files:   map[string]string{"/work/d/a.txt": "a"},
+			line:    "cp -r d d/sub",
+			wantErr: true,
+			want:    map[string]string{"/work/d/sub/d/a.txt": ""},
+		},
+	})
+}
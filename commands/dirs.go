@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	cmdPushd = Cmd{
+		Name: "pushd",
+		Args: []string{"[<dir> | +N | -N]"},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			if len(args) > 1 {
+				return usageError("pushd: too many arguments")
+			}
+			return handlePushd(ctx, c, args)
+		},
+	}
+
+	cmdPopd = Cmd{
+		Name: "popd",
+		Args: []string{"[+N | -N]"},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			if len(args) > 1 {
+				return usageError("popd: too many arguments")
+			}
+			return handlePopd(c, args)
+		},
+	}
+
+	cmdDirs = Cmd{
+		Name: "dirs",
+		Args: []string{"[-clv]"},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			opts, rest, err := getopt("dirs", args, "clv")
+			if err != nil {
+				return err
+			}
+			if len(rest) > 0 {
+				return usageError("dirs: too many arguments")
+			}
+			return handleDirs(c, opts.has('c'), opts.has('l'), opts.has('v'))
+		},
+	}
+)
+
+// DirStack pushd/popd 디렉터리 스택. 현재 디렉터리(Pwd)는 들어있지 않고,
+// dirs 목록의 0번은 항상 Pwd다.
+type DirStack struct {
+	mu   sync.Mutex
+	dirs []string
+}
+
+func (s *DirStack) List() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.dirs...)
+}
+
+func (s *DirStack) set(dirs []string) {
+	s.mu.Lock()
+	s.dirs = dirs
+	s.mu.Unlock()
+}
+
+func (c *Context) dirStack() *DirStack {
+	if c.Dirs == nil {
+		c.Dirs = &DirStack{}
+	}
+	return c.Dirs
+}
+
+// fullStack [Pwd, stack...]
+func (c *Context) fullStack() []string {
+	pwd, _ := c.Pwd.Get()
+	return append([]string{pwd}, c.dirStack().List()...)
+}
+
+// stackIndex "+N"/"-N" → fullStack 인덱스. 숫자 인자가 아니면 ok == false.
+// 부호 뒤에는 숫자만 온다 ("+-1", "--1"은 숫자 인자가 아니다)
+func stackIndex(arg string, n int) (int, bool, error) {
+	if len(arg) < 2 || (arg[0] != '+' && arg[0] != '-') {
+		return 0, false, nil
+	}
+	for i := 1; i < len(arg); i++ {
+		if arg[i] < '0' || arg[i] > '9' {
+			return 0, false, nil
+		}
+	}
+	k, err := strconv.Atoi(arg[1:])
+	if err != nil || k >= n {
+		// 숫자만 있으니 실패는 너무 큰 수
+		return 0, true, fmt.Errorf("%s: directory stack index out of range", arg)
+	}
+	if arg[0] == '-' {
+		k = n - 1 - k
+	}
+	return k, true, nil
+}
+
+func handlePushd(ctx context.Context, c *Context, args []string) error {
+	full := c.fullStack()
+
+	var next []string
+	switch {
+	case len(args) == 0:
+		// 인자 없으면 위 두 개 교환
+		if len(full) < 2 {
+			return errors.New("pushd: no other directory")
+		}
+		next = append([]string{full[1], full[0]}, full[2:]...)
+	default:
+		k, indexed, err := stackIndex(args[0], len(full))
+		if err != nil {
+			return fmt.Errorf("pushd: %w", err)
+		}
+		if indexed {
+			// +N: N번째가 맨 앞으로 오도록 회전
+			next = append(append([]string{}, full[k:]...), full[:k]...)
+			break
+		}
+		fp, err := resolveDir(ctx, c, args[0], c.argLiteral(args)[0])
+		if err != nil {
+			return err
+		}
+		next = append([]string{fp}, full...)
+	}
+
+	if next[0] != full[0] {
+		if err := changeDir(c, next[0]); err != nil {
+			return err
+		}
+	}
+	c.dirStack().set(next[1:])
+	return printDirs(c, false)
+}
+
+func handlePopd(c *Context, args []string) error {
+	full := c.fullStack()
+	if len(full) < 2 {
+		return errors.New("popd: directory stack empty")
+	}
+
+	k := 0
+	if len(args) > 0 {
+		idx, indexed, err := stackIndex(args[0], len(full))
+		if err != nil {
+			return fmt.Errorf("popd: %w", err)
+		}
+		if !indexed {
+			return usageError("popd: %s: invalid argument", args[0])
+		}
+		k = idx
+	}
+
+	next := append(append([]string{}, full[:k]...), full[k+1:]...)
+	if k == 0 {
+		// 맨 위를 빼면 다음 디렉터리로 이동
+		if err := changeDir(c, next[0]); err != nil {
+			return err
+		}
+	}
+	c.dirStack().set(next[1:])
+	return printDirs(c, false)
+}
+
+func handleDirs(c *Context, clear, long, verbose bool) error {
+	if clear {
+		c.dirStack().set(nil)
+		return nil
+	}
+	if verbose {
+		var lines []string
+		for i, d := range c.fullStack() {
+			if !long {
+				d = tildePath(d)
+			}
+			lines = append(lines, fmt.Sprintf("%2d  %s", i, dirText(d)))
+		}
+		_, err := c.ConsoleBuf.WriteString(strings.Join(lines, "\n"))
+		return err
+	}
+	return printDirs(c, long)
+}
+
+func printDirs(c *Context, long bool) error {
+	full := c.fullStack()
+	for i := range full {
+		if !long {
+			full[i] = tildePath(full[i])
+		}
+		full[i] = dirText(full[i])
+	}
+	_, err := c.ConsoleBuf.WriteString(strings.Join(full, " "))
+	return err
+}
+
+// tildePath 홈 디렉터리를 "~"로 줄여 표시
+func tildePath(p string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return p
+	}
+	if p == home {
+		return "~"
+	}
+	if rel, err := filepath.Rel(home, p); err == nil && !strings.HasPrefix(rel, "..") {
+		return "~" + string(filepath.Separator) + rel
+	}
+	return p
+}
+
+// Prompt 터미널 프롬프트 라벨: 현재 디렉터리 이름과 디렉터리 스택
+func (c *Context) Prompt() string {
+	full := c.fullStack()
+	names := make([]string, len(full))
+	for i, d := range full {
+		names[i] = filepath.Base(d)
+		if tildePath(d) == "~" {
+			names[i] = "~"
+		}
+	}
+	if len(names) == 1 {
+		return names[0] + " >"
+	}
+	return names[0] + " [" + strings.Join(names[1:], " ") + "] >"
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+func TestStackIndex(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    int
+		indexed bool
+		wantErr bool
+	}{
+		{arg: "+0", want: 0, indexed: true},
+		{arg: "+2", want: 2, indexed: true},
+		{arg: "-0", want: 2, indexed: true},
+		{arg: "-2", want: 0, indexed: true},
+		{arg: "+3", indexed: true, wantErr: true},
+		{arg: "-3", indexed: true, wantErr: true},
+		{arg: "+99999999999999999999", indexed: true, wantErr: true},
+		{arg: "+-1"},
+		{arg: "--1"},
+		{arg: "-+1"},
+		{arg: "++1"},
+		{arg: "+1a"},
+		{arg: "+"},
+		{arg: "dir"},
+	}
+	for _, tt := range tests {
+		k, indexed, err := stackIndex(tt.arg, 3)
+		if (err != nil) != tt.wantErr || indexed != tt.indexed || (err == nil && k != tt.want) {
+			t.Errorf("stackIndex(%q, 3) = %d, %v, %v; want %d, %v, err %v", tt.arg, k, indexed, err, tt.want, tt.indexed, tt.wantErr)
+		}
+	}
+}
+
+// 스택 [/a /b /c] (Pwd /a)에서 시작
+func TestPushdPopd(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string // fullStack
+		wantErr bool
+	}{
+		{line: "pushd", want: []string{"/b", "/a", "/c"}},
+		{line: "pushd +1", want: []string{"/b", "/c", "/a"}},
+		{line: "pushd +2", want: []string{"/c", "/a", "/b"}},
+		{line: "pushd -0", want: []string{"/c", "/a", "/b"}},
+		{line: "pushd +0", want: []string{"/a", "/b", "/c"}},
+		{line: "pushd /d", want: []string{"/d", "/a", "/b", "/c"}},
+		{line: "pushd +3", want: []string{"/a", "/b", "/c"}, wantErr: true},
+		{line: "pushd +-1", want: []string{"/a", "/b", "/c"}, wantErr: true},
+		{line: "popd", want: []string{"/b", "/c"}},
+		{line: "popd +1", want: []string{"/a", "/c"}},
+		{line: "popd -0", want: []string{"/a", "/b"}},
+		{line: "popd +3", want: []string{"/a", "/b", "/c"}, wantErr: true},
+		{line: "popd --1", want: []string{"/a", "/b", "/c"}, wantErr: true},
+		{line: "popd +-1", want: []string{"/a", "/b", "/c"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			// cd처럼 방문을 기록하므로 경우마다 새 기록 파일
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("AppData", t.TempDir())
+
+			fsys := vfs.NewMem()
+			for _, d := range []string{"/a", "/b", "/c", "/d"} {
+				if err := fsys.MkdirAll(d, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c, _ := newTestContext(fsys, "/a")
+			c.Dirs.set([]string{"/b", "/c"})
+
+			err := run(c, tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := c.fullStack(); !slices.Equal(got, tt.want) {
+				t.Errorf("stack = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
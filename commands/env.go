@@ -0,0 +1,243 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+var (
+	cmdExport = Cmd{
+		Name: "export",
+		Args: []string{"[NAME[=value]...]"},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			return handleExport(c, args)
+		},
+	}
+
+	cmdUnset = Cmd{
+		Name: "unset",
+		Args: []string{"<NAME>..."},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			return handleUnset(c, args)
+		},
+	}
+
+	cmdEnv = Cmd{
+		Name:  "env",
+		Usage: "env",
+		Exec: func(_ context.Context, c *Context, _ []string) error {
+			return handleEnv(c)
+		},
+	}
+)
+
+var varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// 내장 변수: 세션 상태에 묶여 있어 Env 맵에 저장하지 않는다
+const (
+	varPwd    = "PWD" // Pathfinder.CurrentDir
+	varSel    = "SEL" // 파일 트리에서 선택한 파일
+	varStatus = "?"   // 마지막 명령 결과
+)
+
+// Env 터미널 세션별 환경 변수. 프로세스 환경에서 시작한다.
+type Env struct {
+	mu     sync.RWMutex
+	vars   map[string]string
+	status int
+}
+
+func NewEnv(environ []string) *Env {
+	e := &Env{vars: make(map[string]string, len(environ))}
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && k != "" {
+			e.vars[k] = v
+		}
+	}
+	return e
+}
+
+func (e *Env) Get(name string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	v, ok := e.vars[name]
+	return v, ok
+}
+
+func (e *Env) Set(name, value string) {
+	e.mu.Lock()
+	e.vars[name] = value
+	e.mu.Unlock()
+}
+
+func (e *Env) Unset(name string) {
+	e.mu.Lock()
+	delete(e.vars, name)
+	e.mu.Unlock()
+}
+
+// Environ "NAME=value" 목록 (이름순)
+func (e *Env) Environ() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make([]string, 0, len(e.vars))
+	for k, v := range e.vars {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (e *Env) Status() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
+
+func (e *Env) setStatus(code int) {
+	e.mu.Lock()
+	e.status = code
+	e.mu.Unlock()
+}
+
+// lookupVar $VAR 확장용. 내장 변수 → 세션 Env 순으로 찾는다.
+func (c *Context) lookupVar(name string) (string, bool) {
+	switch name {
+	case varPwd:
+		if c.Pwd != nil {
+			v, err := c.Pwd.Get()
+			return v, err == nil
+		}
+	case varSel:
+		if c.Selected != nil {
+			v, err := c.Selected.Get()
+			return v, err == nil
+		}
+		return "", false
+	case varStatus:
+		if c.Env == nil {
+			return "0", true
+		}
+		return strconv.Itoa(c.Env.Status()), true
+	}
+
+	if c.Env == nil {
+		return os.LookupEnv(name)
+	}
+	return c.Env.Get(name)
+}
+
+// setVar export NAME=value. 내장 변수는 세션 상태를 바꾼다.
+func setVar(c *Context, name, value string) error {
+	switch name {
+	case varPwd:
+		fp, err := resolvePath(c, value)
+		if err != nil {
+			return err
+		}
+		if !vfs.IsDir(c.fsys(), fp) {
+			return fmt.Errorf("export: not a directory: %s", value)
+		}
+		if err = c.Pwd.Set(fp); err != nil {
+			return err
+		}
+		c.refreshSideBar()
+		return nil
+	case varSel:
+		if c.Selected == nil {
+			return errors.New("export: SEL is not available")
+		}
+		fp, err := resolvePath(c, value)
+		if err != nil {
+			return err
+		}
+		return c.Selected.Set(fp)
+	}
+
+	if c.Env == nil {
+		return errors.New("export: no environment")
+	}
+	c.Env.Set(name, value)
+	return nil
+}
+
+func handleExport(c *Context, args []string) error {
+	if len(args) == 0 {
+		return handleEnv(c)
+	}
+
+	var errs []error
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !varName.MatchString(name) {
+			errs = append(errs, fmt.Errorf("export: `%s': not a valid identifier", arg))
+			continue
+		}
+		if !hasValue {
+			// 값 없이 export NAME: 이미 있으면 그대로, 없으면 빈 값
+			if _, ok := c.lookupVar(name); ok {
+				continue
+			}
+		}
+		if err := setVar(c, name, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func handleUnset(c *Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("unset: missing argument")
+	}
+
+	var errs []error
+	for _, name := range args {
+		switch {
+		case name == varPwd || name == varSel:
+			errs = append(errs, fmt.Errorf("unset: %s: cannot unset", name))
+		case !varName.MatchString(name):
+			errs = append(errs, fmt.Errorf("unset: `%s': not a valid identifier", name))
+		case c.Env != nil:
+			c.Env.Unset(name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func handleEnv(c *Context) error {
+	var vars []string
+	if c.Env != nil {
+		vars = c.Env.Environ()
+	} else {
+		vars = os.Environ()
+		sort.Strings(vars)
+	}
+
+	// 내장 변수는 현재 세션 값으로
+	out := make([]string, 0, len(vars)+2)
+	for _, kv := range vars {
+		if strings.HasPrefix(kv, varPwd+"=") || strings.HasPrefix(kv, varSel+"=") {
+			continue
+		}
+		out = append(out, kv)
+	}
+	for _, name := range []string{varPwd, varSel} {
+		if v, ok := c.lookupVar(name); ok && v != "" {
+			out = append(out, name+"="+v)
+		}
+	}
+	sort.Strings(out)
+
+	_, err := c.ConsoleBuf.WriteString(strings.Join(out, "\n"))
+	return err
+}
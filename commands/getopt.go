@@ -0,0 +1,86 @@
+package commands
+
+import "strings"
+
+// options getopt 결과. 값이 없는 옵션은 빈 문자열로 들어간다.
+type options map[byte]string
+
+func (o options) has(f byte) bool {
+	_, ok := o[f]
+	return ok
+}
+
+func (o options) get(f byte) string {
+	return o[f]
+}
+
+// getopt POSIX getopt 스타일 옵션 파싱
+// spec은 "pm:"처럼 옵션 문자를 나열하고, 값을 받는 옵션 뒤에는 ':'를 붙인다.
+// "-rf" 같은 묶음, "-mVALUE"/"-m VALUE", "--" 종료를 지원하며 첫 피연산자에서 파싱을 멈춘다.
+func getopt(name string, args []string, spec string) (options, []string, error) {
+	opts := options{}
+	i := 0
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			i++
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+
+		for j := 1; j < len(arg); j++ {
+			f := arg[j]
+			k := strings.IndexByte(spec, f)
+			if k < 0 || f == ':' {
+				return nil, nil, usageError("%s: invalid option -- '%c'", name, f)
+			}
+			if k+1 < len(spec) && spec[k+1] == ':' {
+				// 값을 받는 옵션: 나머지 글자 또는 다음 인자
+				if j+1 < len(arg) {
+					opts[f] = arg[j+1:]
+				} else if i+1 < len(args) {
+					i++
+					opts[f] = args[i]
+				} else {
+					return nil, nil, usageError("%s: option requires an argument -- '%c'", name, f)
+				}
+				break
+			}
+			opts[f] = ""
+		}
+	}
+	return opts, args[i:], nil
+}
+
+// longOpt 값을 받는 긴 옵션("--name VALUE", "--name=VALUE")을 모두 꺼낸다. 여러 번 줄 수 있다.
+// 위치와 상관없이 찾고, 나머지 인자는 순서대로 돌려준다. "--" 뒤는 건드리지 않는다.
+// literal은 args와 나란히, 나머지 인자에 맞춰 돌려준다 (argLiteral)
+func longOpt(name string, args []string, literal []bool, long string) ([]string, []string, []bool, error) {
+	var vals, rest []string
+	var restLiteral []bool
+	flag := "--" + long
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			for j := i; j < len(args); j++ {
+				restLiteral = append(restLiteral, isLiteral(literal, j))
+			}
+			return vals, append(rest, args[i:]...), restLiteral, nil
+		case arg == flag:
+			if i+1 >= len(args) {
+				return nil, nil, nil, usageError("%s: option '%s' requires an argument", name, flag)
+			}
+			i++
+			vals = append(vals, args[i])
+		case strings.HasPrefix(arg, flag+"="):
+			vals = append(vals, arg[len(flag)+1:])
+		default:
+			rest = append(rest, arg)
+			restLiteral = append(restLiteral, isLiteral(literal, i))
+		}
+	}
+	return vals, rest, restLiteral, nil
+}
@@ -0,0 +1,391 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// braceRange {1..10}, {01..10..2}, {a..e}
+var braceRange = regexp.MustCompile(`^(-?\d+|[a-zA-Z])\.\.(-?\d+|[a-zA-Z])(?:\.\.(-?\d+))?$`)
+
+// maxBraceExpansions 한 명령의 중괄호 확장으로 만들 수 있는 단어 수 ({1..99999999}, {a,b}{a,b}... 방지)
+const maxBraceExpansions = 100000
+
+var errTooManyBraces = fmt.Errorf("brace expansion: more than %d words", maxBraceExpansions)
+
+// expandBraces bash 스타일 중괄호 확장. {a,b}, {1..10}, 중첩 {a,b{c,d}} 지원
+// 확장할 것이 없는 "{...}"는 글자 그대로 둔다. 결과가 limit개를 넘으면 오류
+func expandBraces(s string, limit int) ([]string, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '{' {
+			continue
+		}
+		j := matchingBrace(s, i)
+		if j < 0 {
+			break
+		}
+		alts, ok, err := braceAlternatives(s[i+1:j], limit)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		var out []string
+		for _, alt := range alts {
+			words, err := expandBraces(s[:i]+alt+s[j+1:], limit-len(out))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, words...)
+		}
+		return out, nil
+	}
+	if limit < 1 {
+		return nil, errTooManyBraces
+	}
+	return []string{s}, nil
+}
+
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// braceAlternatives 중괄호 안쪽을 최상위 ',' 기준으로 나누거나 범위를 펼친다
+func braceAlternatives(inner string, limit int) ([]string, bool, error) {
+	var alts []string
+	depth, last := 0, 0
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alts = append(alts, inner[last:i])
+				last = i + 1
+			}
+		}
+	}
+	if alts != nil {
+		return append(alts, inner[last:]), true, nil
+	}
+	return expandRange(inner, limit)
+}
+
+func expandRange(inner string, limit int) ([]string, bool, error) {
+	m := braceRange.FindStringSubmatch(inner)
+	if m == nil {
+		return nil, false, nil
+	}
+
+	step := 1
+	if m[3] != "" {
+		step, _ = strconv.Atoi(m[3])
+		if step < 0 {
+			step = -step
+		}
+		if step == 0 {
+			step = 1
+		}
+	}
+
+	from, errFrom := strconv.Atoi(m[1])
+	to, errTo := strconv.Atoi(m[2])
+	if errors.Is(errFrom, strconv.ErrRange) || errors.Is(errTo, strconv.ErrRange) {
+		return nil, false, errTooManyBraces
+	}
+	numeric := errFrom == nil && errTo == nil
+	if !numeric {
+		if errFrom == nil || errTo == nil {
+			// 숫자와 문자를 섞은 범위는 확장하지 않음
+			return nil, false, nil
+		}
+		from, to = int(m[1][0]), int(m[2][0])
+	}
+	// 만들기 전에 개수부터 본다 (뺄셈이 넘치면 음수)
+	if n := (max(from, to)-min(from, to))/step + 1; n > limit || n < 1 {
+		return nil, false, errTooManyBraces
+	}
+
+	// 앞자리 0이 있으면 같은 폭으로 채움 ({01..10})
+	width := 0
+	if numeric && (hasLeadingZero(m[1]) || hasLeadingZero(m[2])) {
+		width = max(len(m[1]), len(m[2]))
+	}
+
+	var out []string
+	add := func(v int) {
+		switch {
+		case !numeric:
+			out = append(out, string(rune(v)))
+		case width > 0:
+			out = append(out, fmt.Sprintf("%0*d", width, v))
+		default:
+			out = append(out, strconv.Itoa(v))
+		}
+	}
+	if from <= to {
+		for v := from; v <= to; v += step {
+			add(v)
+		}
+	} else {
+		for v := from; v >= to; v -= step {
+			add(v)
+		}
+	}
+	return out, true, nil
+}
+
+func hasLeadingZero(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return len(s) > 1 && s[0] == '0'
+}
+
+// expandBraceArgs 인자마다 중괄호 확장. 모두 합쳐 maxBraceExpansions개까지.
+// 따옴표로 감싼 인자(literal[i])는 그대로 두고, 결과와 나란한 literal을 함께 돌려준다
+func expandBraceArgs(args []string, literal []bool) ([]string, []bool, error) {
+	out := make([]string, 0, len(args))
+	outLiteral := make([]bool, 0, len(args))
+	for i, a := range args {
+		if isLiteral(literal, i) {
+			out = append(out, a)
+			outLiteral = append(outLiteral, true)
+			continue
+		}
+		words, err := expandBraces(a, maxBraceExpansions-len(out))
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, words...)
+		outLiteral = append(outLiteral, make([]bool, len(words))...)
+	}
+	return out, outLiteral, nil
+}
+
+// hasGlob: 글롭 문자가 있는지 (*, ?, [], **, !(...))
+func hasGlob(p string) bool { return strings.ContainsAny(p, "*?[") || strings.Contains(p, "!(") }
+
+// expandPattern 절대 경로 패턴을 글롭 확장한다.
+// "**"는 0개 이상의 디렉터리, "!(a|b)"는 a, b에 매치되지 않는 이름에 대응한다.
+// dotfile 규칙은 filepath.Glob을 쓰던 때처럼 마지막 세그먼트에만: "*" 류는 dotfile 제외, ".*" 류는 dotfile만.
+// 중간 세그먼트의 "*"는 숨김 디렉터리에도 매치되고("*/a.txt" → ".git/a.txt"), "**"는 숨김 디렉터리로 내려가지 않는다.
+func expandPattern(ctx context.Context, fsys vfs.ReadFS, p string) ([]string, error) {
+	if !hasGlob(p) {
+		return []string{p}, nil
+	}
+
+	root, segs := splitPattern(p)
+	seen := map[string]struct{}{}
+	var matches []string
+	if err := globSegments(ctx, fsys, root, segs, seen, &matches); err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no matches for %q", p)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// splitPattern 글롭이 없는 앞부분(root)과 나머지 세그먼트로 나눈다
+func splitPattern(p string) (string, []string) {
+	vol := filepath.VolumeName(p)
+	rest := strings.TrimPrefix(p[len(vol):], string(filepath.Separator))
+	root := vol + string(filepath.Separator)
+	if !filepath.IsAbs(p) {
+		root, rest = ".", p
+	}
+
+	var segs []string
+	for _, s := range strings.Split(rest, string(filepath.Separator)) {
+		if s != "" {
+			segs = append(segs, s)
+		}
+	}
+	for len(segs) > 0 && !hasGlob(segs[0]) {
+		root = filepath.Join(root, segs[0])
+		segs = segs[1:]
+	}
+	return root, segs
+}
+
+func globSegments(ctx context.Context, fsys vfs.ReadFS, base string, segs []string, seen map[string]struct{}, out *[]string) error {
+	// "**"는 큰 트리를 오래 돌 수 있다
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(segs) == 0 {
+		if _, dup := seen[base]; !dup && vfs.Exists(fsys, base) {
+			seen[base] = struct{}{}
+			*out = append(*out, base)
+		}
+		return nil
+	}
+
+	seg := segs[0]
+	if seg == "**" {
+		// 0개 디렉터리
+		if err := globSegments(ctx, fsys, base, segs[1:], seen, out); err != nil {
+			return err
+		}
+		// 1개 이상: 하위 디렉터리마다 "**" 유지한 채 내려감 (심볼릭 링크는 따라가지 않음)
+		ents, err := fsys.ReadDir(base)
+		if err != nil {
+			return nil
+		}
+		for _, e := range ents {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			next := segs
+			if !e.IsDir() {
+				if len(segs) > 1 {
+					continue
+				}
+				// 마지막 "**"는 파일까지 모두 매치
+				next = nil
+			}
+			if err = globSegments(ctx, fsys, filepath.Join(base, e.Name()), next, seen, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !hasGlob(seg) {
+		return globSegments(ctx, fsys, filepath.Join(base, seg), segs[1:], seen, out)
+	}
+
+	ents, err := fsys.ReadDir(base)
+	if err != nil {
+		// 디렉터리가 아니거나 읽을 수 없으면 매치 없음 (filepath.Glob과 동일)
+		return nil
+	}
+	for _, e := range ents {
+		name := e.Name()
+		if len(segs) == 1 && strings.HasPrefix(name, ".") != strings.HasPrefix(seg, ".") {
+			// 마지막 세그먼트 "*" 류: dotfile 제외 / ".*" 류: dotfile만
+			continue
+		}
+		ok, err := matchSegment(seg, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err = globSegments(ctx, fsys, filepath.Join(base, name), segs[1:], seen, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchSegment 한 경로 세그먼트 매치. filepath.Match에 "!(a|b)" 제외 패턴을 더한다.
+func matchSegment(pat, name string) (bool, error) {
+	i := strings.Index(pat, "!(")
+	if i < 0 {
+		return filepath.Match(pat, name)
+	}
+	j := matchingParen(pat, i+1)
+	if j < 0 {
+		return false, filepath.ErrBadPattern
+	}
+	prefix, alts, suffix := pat[:i], splitAlternatives(pat[i+2:j]), pat[j+1:]
+
+	// name = head + mid + tail 로 나눠 head는 prefix, tail은 suffix에 매치되고
+	// mid는 어떤 대안에도 매치되지 않는 분할이 있으면 성공
+	for k := 0; k <= len(name); k++ {
+		ok, err := filepath.Match(prefix, name[:k])
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+		for m := k; m <= len(name); m++ {
+			ok, err = matchSegment(suffix, name[m:])
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				continue
+			}
+			excluded, err := matchAny(alts, name[k:m])
+			if err != nil {
+				return false, err
+			}
+			if !excluded {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func splitAlternatives(s string) []string {
+	var alts []string
+	depth, last := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				alts = append(alts, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(alts, s[last:])
+}
+
+func matchAny(pats []string, name string) (bool, error) {
+	for _, p := range pats {
+		ok, err := matchSegment(p, name)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
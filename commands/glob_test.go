@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string // 공백으로 이은 결과
+		wantErr bool
+	}{
+		{in: "a", want: "a"},
+		{in: "{a,b}", want: "a b"},
+		{in: "x{a,b}y", want: "xay xby"},
+		{in: "{a,b{c,d}}", want: "a bc bd"},
+		{in: "{1..3}", want: "1 2 3"},
+		{in: "{3..1}", want: "3 2 1"},
+		{in: "{01..10..3}", want: "01 04 07 10"},
+		{in: "{a..c}", want: "a b c"},
+		{in: "{a..3}", want: "{a..3}"},
+		{in: "{}", want: "{}"},
+		{in: "{a", want: "{a"},
+		{in: "{1..100000}", want: ""},
+		{in: "{1..100001}", wantErr: true},
+		{in: "{1..99999999999999999999}", wantErr: true},
+		{in: "{-9000000000000000000..9000000000000000000}", wantErr: true},
+		// 곱으로 늘어나는 것도 센다: 10^6
+		{in: "{0..9}{0..9}{0..9}{0..9}{0..9}{0..9}", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := expandBraces(tt.in, maxBraceExpansions)
+		if tt.wantErr {
+			if !errors.Is(err, errTooManyBraces) {
+				t.Errorf("expandBraces(%q) err = %v, want errTooManyBraces", tt.in, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expandBraces(%q) err = %v", tt.in, err)
+			continue
+		}
+		if tt.want == "" {
+			if len(got) != maxBraceExpansions {
+				t.Errorf("expandBraces(%q) = %d words, want %d", tt.in, len(got), maxBraceExpansions)
+			}
+			continue
+		}
+		if s := strings.Join(got, " "); s != tt.want {
+			t.Errorf("expandBraces(%q) = %q, want %q", tt.in, s, tt.want)
+		}
+	}
+}
+
+// 한도는 명령의 모든 인자를 합친 것이다
+func TestExpandBraceArgsLimit(t *testing.T) {
+	if _, _, err := expandBraceArgs([]string{"{1..60000}", "{1..50000}"}, nil); !errors.Is(err, errTooManyBraces) {
+		t.Errorf("err = %v, want errTooManyBraces", err)
+	}
+	got, _, err := expandBraceArgs([]string{"{1..2}", "x", "{a,b}"}, nil)
+	if err != nil || strings.Join(got, " ") != "1 2 x a b" {
+		t.Errorf("expandBraceArgs = %v, %v", got, err)
+	}
+
+	// 따옴표로 감싼 인자는 그대로, literal은 결과와 나란히
+	got, literal, err := expandBraceArgs([]string{"{a,b}", "{a,b}", "x"}, []bool{true, false, false})
+	if err != nil || strings.Join(got, " ") != "{a,b} a b x" || !slices.Equal(literal, []bool{true, false, false, false}) {
+		t.Errorf("expandBraceArgs = %v, %v, %v", got, literal, err)
+	}
+
+	c, _ := newTestContext(vfs.NewMem(), "/")
+	if err := run(c, "mkdir {1..200000}"); err == nil || !strings.Contains(err.Error(), "mkdir: brace expansion") {
+		t.Errorf("mkdir err = %v", err)
+	}
+}
+
+func TestExpandPattern(t *testing.T) {
+	fsys := vfs.NewMem()
+	writeFiles(t, fsys, map[string]string{
+		"/w/a.txt":            "a",
+		"/w/b.log":            "b",
+		"/w/.env":             "e",
+		"/w/.git/a.txt":       "g",
+		"/w/src/a.txt":        "s",
+		"/w/src/x/a.txt":      "x",
+		"/w/src/.cache/a.txt": "c",
+	})
+	tests := []struct {
+		pat  string
+		want string // 공백으로 이은 결과, ""이면 매치 없음
+	}{
+		// 마지막 세그먼트만 dotfile 규칙
+		{pat: "/w/*", want: "/w/a.txt /w/b.log /w/src"},
+		{pat: "/w/.*", want: "/w/.env /w/.git"},
+		{pat: "/w/*/a.txt", want: "/w/.git/a.txt /w/src/a.txt"},
+		{pat: "/w/.*/a.txt", want: "/w/.git/a.txt"},
+		// "**"는 0개 이상의 디렉터리, 숨김 디렉터리로는 내려가지 않는다
+		{pat: "/w/**/a.txt", want: "/w/a.txt /w/src/a.txt /w/src/x/a.txt"},
+		{pat: "/w/src/**", want: "/w/src /w/src/a.txt /w/src/x /w/src/x/a.txt"},
+		// "!(…)" 제외
+		{pat: "/w/!(*.log)", want: "/w/a.txt /w/src"},
+		{pat: "/w/!(a|src).*", want: "/w/b.log"},
+		{pat: "/w/*.none", want: ""},
+	}
+	for _, tt := range tests {
+		got, err := expandPattern(context.Background(), fsys, tt.pat)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("expandPattern(%q) = %q, want no matches", tt.pat, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expandPattern(%q) err = %v", tt.pat, err)
+			continue
+		}
+		if s := strings.Join(got, " "); s != tt.want {
+			t.Errorf("expandPattern(%q) = %q, want %q", tt.pat, s, tt.want)
+		}
+	}
+}
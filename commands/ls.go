@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+var cmdLs = Cmd{
+	Name: "ls",
+	Args: []string{"[-a]", "[<path>...]"},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		opts, paths, err := getopt("ls", args, "a")
+		if err != nil {
+			return err
+		}
+		paths, literal, err := expandBraceArgs(paths, c.argLiteral(paths))
+		if err != nil {
+			return fmt.Errorf("ls: %w", err)
+		}
+		return handleList(ctx, c, paths, literal, opts.has('a'))
+	},
+}
+
+var lsColumns = []Column{
+	{Name: "name", Kind: ColPath},
+	{Name: "size", Kind: ColSize},
+	{Name: "mode", Kind: ColText},
+	{Name: "modified", Kind: ColTime},
+}
+
+// handleList literal[i]이면 specs[i]를 글롭 확장하지 않는다
+func handleList(ctx context.Context, c *Context, specs []string, literal []bool, all bool) error {
+	if len(specs) == 0 {
+		specs = []string{"."}
+	}
+
+	base, err := baseDir(c)
+	if err != nil {
+		return err
+	}
+	fsys := c.fsys()
+	t := NewTable(lsColumns...)
+	t.Dir = base
+
+	var errs []error
+	for i, spec := range specs {
+		fp, err := resolvePath(c, spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		matches := []string{fp}
+		if !isLiteral(literal, i) {
+			if matches, err = expandPattern(ctx, fsys, fp); err != nil {
+				errs = append(errs, fmt.Errorf("ls: %w", err))
+				continue
+			}
+		}
+
+		for _, m := range matches {
+			fi, err := fsys.Stat(m)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("ls: %w", err))
+				continue
+			}
+			if !fi.IsDir() && !vfs.IsArchiveDir(fsys, m) {
+				appendEntry(t, m, fi)
+				continue
+			}
+			// 디렉터리 하나만 보면 이름만 나오도록
+			if len(specs) == 1 && len(matches) == 1 {
+				t.Dir = m
+			}
+			if err = listDir(fsys, t, m, all); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(t.Rows) > 0 {
+		if err := c.ConsoleBuf.WriteTable(t); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func listDir(fsys vfs.ReadFS, t *Table, dir string, all bool) error {
+	ents, err := fsys.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range ents {
+		if !all && strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		fp := filepath.Join(dir, e.Name())
+		// 심볼릭 링크는 대상 정보로
+		fi, err := fsys.Stat(fp)
+		if err != nil {
+			if fi, err = e.Info(); err != nil {
+				continue
+			}
+		}
+		appendEntry(t, fp, fi)
+	}
+	return nil
+}
+
+func appendEntry(t *Table, fp string, fi fs.FileInfo) {
+	if fi.IsDir() {
+		t.AppendDir(fp, nil, fi.Mode().String(), fi.ModTime())
+		return
+	}
+	t.Append(fp, fi.Size(), fi.Mode().String(), fi.ModTime())
+}
+
+// OpenPath 출력 표에서 경로를 눌렀을 때: 디렉터리면 cd, 파일이면 선택(미리보기)
+func OpenPath(ctx context.Context, c *Context, p string) error {
+	fi, err := c.fsys().Stat(p)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return handleChangeDirectory(ctx, c, p, true)
+	}
+	if c.Selected == nil {
+		return fmt.Errorf("%s: not a directory", p)
+	}
+	return c.Selected.Set(p)
+}
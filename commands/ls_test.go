@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// ls는 표를 만들 때 디렉터리인 행을 적어 둔다 (터미널이 칸마다 Stat하지 않게)
+func TestListMarksDirs(t *testing.T) {
+	fsys := vfs.NewMem()
+	writeFiles(t, fsys, map[string]string{
+		"/work/a.txt":   "a",
+		"/work/d/b.txt": "b",
+	})
+	if err := fsys.Mkdir("/work/e", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newTestContext(fsys, "/work")
+	if err := run(c, "ls"); err != nil {
+		t.Fatal(err)
+	}
+
+	var tbl *Table
+	for _, bl := range c.ConsoleBuf.(*Buffer).Blocks() {
+		if bl.Table != nil {
+			tbl = bl.Table
+		}
+	}
+	if tbl == nil {
+		t.Fatal("ls wrote no table")
+	}
+	want := map[string]bool{"/work/a.txt": false, "/work/d": true, "/work/e": true}
+	if len(tbl.Rows) != len(want) {
+		t.Fatalf("ls rows = %v", tbl.Rows)
+	}
+	for r := range tbl.Rows {
+		p, _ := tbl.Path(r, 0)
+		if dir, ok := want[p]; !ok || tbl.IsDir(r) != dir {
+			t.Errorf("row %s: IsDir = %v, want %v", p, tbl.IsDir(r), dir)
+		}
+	}
+}
@@ -1,33 +1,83 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"strconv"
+	"strings"
 )
 
 var cmdMkdir = Cmd{
 	Name: "mkdir",
-	Args: []string{"<dst>"},
-	Exec: func(c *Context, args []string) error {
-		if len(args) == 0 {
-			return fmt.Errorf("mkdir: missing argument")
+	Args: []string{"[-p]", "[-m mode]", "<dir>..."},
+	Exec: func(_ context.Context, c *Context, args []string) error {
+		opts, dirs, err := getopt("mkdir", args, "pm:")
+		if err != nil {
+			return err
 		}
-		return handleMakeDirectory(c, args[0])
+		if len(dirs) == 0 {
+			return usageError("mkdir: missing operand")
+		}
+
+		perm := fs.FileMode(0o755)
+		if opts.has('m') {
+			m, err := strconv.ParseUint(opts.get('m'), 8, 32)
+			if err != nil || m > 0o777 {
+				return usageError("mkdir: invalid mode %q", opts.get('m'))
+			}
+			perm = fs.FileMode(m)
+		}
+		if dirs, _, err = expandBraceArgs(dirs, c.argLiteral(dirs)); err != nil {
+			return fmt.Errorf("mkdir: %w", err)
+		}
+		return handleMakeDirectory(c, dirs, opts.has('p'), opts.has('m'), perm)
 	},
 }
 
-func handleMakeDirectory(c *Context, dst string) error {
-	fp, err := pathToAbs(c, dst)
-	if err != nil {
-		return err
+func handleMakeDirectory(c *Context, dsts []string, parents, chmod bool, perm fs.FileMode) error {
+	var done []string
+	var errs []error
+	for _, dst := range dsts {
+		fp, err := makeDirectory(c, dst, parents, chmod, perm)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		done = append(done, "mkdir "+dirText(fp))
+	}
+
+	if len(done) > 0 {
+		c.refreshSideBar()
+		if _, err := c.ConsoleBuf.WriteString(strings.Join(done, "\n")); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	err = os.MkdirAll(fp, 0755)
+	return errors.Join(errs...)
+}
+
+func makeDirectory(c *Context, dst string, parents, chmod bool, perm fs.FileMode) (string, error) {
+	fp, err := resolvePath(c, dst)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	c.RefreshSideBar()
+	// -p: 중간 디렉터리까지 생성, 이미 있으면 성공
+	if parents {
+		err = c.fsys().MkdirAll(fp, 0o755)
+	} else {
+		err = c.fsys().Mkdir(fp, perm)
+	}
+	if err != nil {
+		return "", err
+	}
 
-	_, err = fmt.Fprintf(c.ConsoleBuf, "mkdir %s", fp)
-	return err
+	// -m: umask 영향 없이 지정한 모드 그대로
+	if chmod {
+		if err = c.fsys().Chmod(fp, perm); err != nil {
+			return "", err
+		}
+	}
+	return fp, nil
 }
@@ -1,112 +1,169 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+
+	"github.com/meteormin/minder/vfs"
 )
 
 var cmdMove = Cmd{
 	Name: "mv",
-	Args: []string{"<src>", "<dst>"},
-	Exec: func(c *Context, args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("mv: missing argument")
+	Args: []string{"[-k]", "<src>...", "<dst>"},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		opts, operands, err := getopt("mv", args, "k")
+		if err != nil {
+			return err
+		}
+		operands, literal, err := expandBraceArgs(operands, c.argLiteral(operands))
+		if err != nil {
+			return fmt.Errorf("mv: %w", err)
+		}
+		if len(operands) < 2 {
+			return usageError("mv: missing argument")
 		}
-		return handleMove(c, args[0], args[1])
+		last := len(operands) - 1
+		return handleMove(ctx, c, operands[:last], literal[:last], operands[last], opts.has('k'))
 	},
 }
 
-// moveEntry mv도 동일 정책
-func moveEntry(c *Context, srcPattern, dst string) error {
-	// 1) "aDir/." → 내용만 복사
-	if dir, ok := asDotContents(srcPattern); ok {
-		return copyDirContents(c, dir, dst)
-	}
-
-	// 2) 글롭 확장
-	srcs, err := expandPattern(srcPattern)
-	if err != nil {
+// moveEntries copyEntries와 동일 정책
+func moveEntries(ctx context.Context, c *Context, srcPatterns []string, literal []bool, dst string) error {
+	srcs, misses := expandSources(ctx, c.fsys(), srcPatterns, literal)
+	if err := checkMultiTarget(c.fsys(), len(srcs)+len(misses), dst); err != nil {
 		return err
 	}
-	if len(srcs) > 1 {
-		if di, err := os.Stat(dst); err != nil || !di.IsDir() {
-			return fmt.Errorf("target %q is not a directory for multiple sources", dst)
+
+	var errs []error
+	for _, m := range misses {
+		err := c.report.fail(m.pattern, m.err, func(ctx context.Context, c *Context) error {
+			return moveEntries(ctx, c, []string{m.pattern}, nil, dst)
+		})
+		if err != nil {
+			errs = append(errs, err)
 		}
 	}
 	for _, s := range srcs {
-		if dir, ok := asDotContents(s); ok {
-			// "aDir/." → 내용만 이동
-			if err := moveDirContents(c, dir, dst); err != nil {
-				return err
-			}
-			continue
-		}
-		if err = moveAny(c, s, dst); err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
+		err := c.report.fail(s, moveEntry(ctx, c, s, dst), func(ctx context.Context, c *Context) error {
+			return moveEntry(ctx, c, s, dst)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// moveEntry 펼쳐진 소스 하나를 이동. "aDir/."이면 내용만 이동
+func moveEntry(ctx context.Context, c *Context, src, dst string) error {
+	if dir, ok := asDotContents(src); ok {
+		return moveDirContents(ctx, c, dir, dst)
+	}
+	return moveAny(ctx, c, src, dst)
 }
 
 // moveDirContents copyDirContents와 대칭
-func moveDirContents(c *Context, srcDir, dstDir string) error {
-	ents, err := os.ReadDir(srcDir)
+func moveDirContents(ctx context.Context, c *Context, srcDir, dstDir string) error {
+	fsys := c.fsys()
+	ents, err := fsys.ReadDir(srcDir)
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(dstDir, 0o755); err != nil {
+	if err := fsys.MkdirAll(dstDir, 0o755); err != nil {
 		return err
 	}
 	for _, e := range ents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		s := filepath.Join(srcDir, e.Name())
 		d := filepath.Join(dstDir, e.Name())
-		if err = moveAny(c, s, d); err != nil {
+		err = c.report.fail(s, moveAny(ctx, c, s, d), func(ctx context.Context, c *Context) error { return moveAny(ctx, c, s, d) })
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func moveAny(c *Context, src, dst string) error {
+func moveAny(ctx context.Context, c *Context, src, dst string) error {
+	fsys := c.fsys()
 	// dst가 디렉터리면 src 베이스 이름으로 붙임
-	if di, err := os.Stat(dst); err == nil && di.IsDir() {
+	if vfs.IsDir(fsys, dst) {
 		dst = filepath.Join(dst, filepath.Base(src))
 	}
 	// 우선 rename
-	if err := os.Rename(src, dst); err == nil {
+	if err := fsys.Rename(src, dst); err == nil {
+		c.report.succeed()
 		return nil
 	} else if !isCrossDevice(err) && !shouldFallbackRename(err) {
 		// 다른 이유면 그대로 리턴
 		return err
 	}
-	// 폴백: copyAny(+재귀) → remove
-	if err := copyAny(c, src, dst); err != nil {
+	// 폴백: 복사(+재귀) → remove
+	// dst는 이미 최종 경로이므로 copyAny처럼 이름을 다시 붙이지 않는다
+	fi, err := fsys.Lstat(src)
+	if err != nil {
+		return err
+	}
+	copyOne := copyFile
+	if fi.IsDir() {
+		copyOne = copyDir
+	}
+	skipped, failed := c.skipped, c.report.failedCount()
+	if err := copyOne(ctx, c, src, dst); err != nil {
 		return err
 	}
-	return os.RemoveAll(src)
+	if c.skipped > skipped || c.report.failedCount() > failed {
+		// 충돌에서 건너뛰었거나 -k로 일부만 복사됨: 원본을 지우면 데이터가 사라지므로 남겨둔다
+		c.skip(src, "source kept: copy incomplete")
+		if c.report == nil {
+			_, err := fmt.Fprintf(c.ConsoleBuf, "mv: %s kept: some entries were skipped\n", c.pathText(src))
+			return err
+		}
+		return nil
+	}
+	return fsys.RemoveAll(src)
 }
 
-func handleMove(c *Context, src string, dst string) error {
+func handleMove(ctx context.Context, c *Context, srcs []string, literal []bool, dst string, keepGoing bool) error {
 	logger := c.Logger
-	absSrc, err := pathToAbs(c, src)
-	if err != nil {
-		logger.Error("failed path to abs", "src", src)
-		return err
+	absSrcs := make([]string, 0, len(srcs))
+	for _, src := range srcs {
+		absSrc, err := resolveOperand(c, src)
+		if err != nil {
+			logger.Error("failed resolve path", "src", src)
+			return err
+		}
+		absSrcs = append(absSrcs, absSrc)
 	}
-	absDst, err := pathToAbs(c, dst)
+	absDst, err := resolvePath(c, dst)
 	if err != nil {
-		logger.Error("failed path to abs", "dst", dst)
+		logger.Error("failed resolve path", "dst", dst)
 		return err
 	}
 
-	err = moveEntry(c, absSrc, absDst)
+	var rep *opReport
+	sub := c
+	if keepGoing {
+		rep = newReport("mv")
+		sub = c.withReport(rep)
+	}
+
+	err = moveEntries(ctx, sub, absSrcs, literal, absDst)
+	c.refreshSideBar()
 	if err != nil {
 		return err
 	}
+	if rep != nil {
+		return finishReport(c, rep)
+	}
 
-	c.RefreshSideBar()
-
-	_, err = fmt.Fprintf(c.ConsoleBuf, "mv: %s to %s", absSrc, absDst)
+	_, err = fmt.Fprintf(c.ConsoleBuf, "mv: %s to %s", c.pathsText(absSrcs), c.pathText(absDst))
 	return err
 }
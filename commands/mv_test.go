@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+func TestMove(t *testing.T) {
+	runFSCases(t, []fsCase{
+		{
+			name:  "rename",
+			files: map[string]string{"/work/a.txt": "a"},
+			line:  "mv a.txt b.txt",
+			want:  map[string]string{"/work/a.txt": "", "/work/b.txt": "a"},
+		},
+		{
+			name:  "into dir",
+			files: map[string]string{"/work/a.txt": "a", "/work/d/sub/b.txt": "b", "/dst/.keep": "k"},
+			line:  "mv a.txt d /dst",
+			want:  map[string]string{"/work/a.txt": "", "/dst/a.txt": "a", "/dst/d/sub/b.txt": "b"},
+		},
+		{
+			name:  "contents",
+			files: map[string]string{"/work/d/a.txt": "a", "/work/d/b.txt": "b"},
+			line:  "mv d/. /dst",
+			want:  map[string]string{"/work/d/a.txt": "", "/dst/a.txt": "a", "/dst/b.txt": "b"},
+		},
+		{
+			name:    "missing source",
+			files:   map[string]string{"/work/a.txt": "a"},
+			line:    "mv nope.txt b.txt",
+			wantErr: true,
+			want:    map[string]string{"/work/a.txt": "a"},
+		},
+		{
+			name:    "keep going",
+			files:   map[string]string{"/work/a.txt": "a", "/dst/.keep": "k"},
+			line:    "mv -k a.txt nope.txt /dst",
+			wantErr: true,
+			want:    map[string]string{"/work/a.txt": "", "/dst/a.txt": "a"},
+		},
+	})
+}
+
+// 마운트 사이의 mv는 rename이 EXDEV로 실패해 복사 후 삭제로 간다.
+// 충돌에서 건너뛴 파일이 있으면 원본을 지우면 안 된다.
+func TestMoveAcrossMountsKeepsSkipped(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		answers []string
+		want    map[string]string
+	}{
+		{
+			name:    "skip",
+			line:    "mv /src/dir /@r",
+			answers: []string{"skip"},
+			want: map[string]string{
+				"/src/dir/a.txt": "new a",
+				"/src/dir/b.txt": "b", // 원본은 통째로 남는다
+				"/@r/dir/a.txt":  "old a",
+				"/@r/dir/b.txt":  "b",
+			},
+		},
+		{
+			name:    "skip keep going",
+			line:    "mv -k /src/dir /@r",
+			answers: []string{"skip"},
+			want: map[string]string{
+				"/src/dir/a.txt": "new a",
+				"/@r/dir/a.txt":  "old a",
+				"/@r/dir/b.txt":  "b",
+			},
+		},
+		{
+			name: "file",
+			line: "mv /src/dir/b.txt /@r",
+			want: map[string]string{
+				"/src/dir/b.txt": "",
+				"/@r/b.txt":      "b",
+			},
+		},
+		{
+			name: "back to base",
+			line: "mv /@r/dir /src/moved",
+			want: map[string]string{
+				"/@r/dir/a.txt":    "",
+				"/src/moved/a.txt": "old a",
+			},
+		},
+		{
+			name:    "overwrite",
+			line:    "mv /src/dir /@r",
+			answers: []string{"overwrite"},
+			want: map[string]string{
+				"/src/dir/a.txt": "",
+				"/@r/dir/a.txt":  "new a",
+				"/@r/dir/b.txt":  "b",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := vfs.NewMux(vfs.NewMem())
+			if err := mux.Mount("/@r", vfs.NewMem()); err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, mux, map[string]string{
+				"/src/dir/a.txt": "new a",
+				"/src/dir/b.txt": "b",
+				"/@r/dir/a.txt":  "old a",
+			})
+			c, _ := newTestContext(mux, "/src", tt.answers...)
+			if err := run(c, tt.line); err != nil {
+				t.Fatal(err)
+			}
+			checkFiles(t, mux, tt.want)
+			if len(tt.answers) > 0 && tt.answers[0] == "skip" && !vfs.IsDir(mux, "/src/dir") {
+				t.Error("source directory removed although a file was skipped")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// Output 명령 출력. 텍스트와 표(Table)를 순서대로 쌓는다.
+// 터미널은 표를 widget.Table로, 파이프/리다이렉트는 TSV나 JSON으로 바꿔 쓴다.
+type Output interface {
+	io.Writer
+	io.StringWriter
+	io.ByteWriter
+	WriteTable(t *Table) error
+	Len() int
+	Reset()
+	String() string
+}
+
+// chunkLines 텍스트 블록 하나의 최대 줄 수. 넘으면 줄 경계에서 새 블록을 시작해
+// 스크롤백을 블록 단위로 버리고, 화면도 마지막 블록만 다시 그리게 한다.
+const chunkLines = 200
+
+// Block 출력 한 덩어리: Text 또는 Table 중 하나
+type Block struct {
+	ID    int // 버퍼 안에서 증가하는 번호 (Reset 뒤에도 이어진다)
+	Text  string
+	Table *Table
+
+	lines int // 텍스트는 줄바꿈 수, 표는 행 수 + 1
+}
+
+// Buffer 기본 Output 구현. 여러 고루틴에서 써도 된다.
+// MaxLines가 0보다 크면 넘친 줄만큼 오래된 블록부터 버린다 (스크롤백).
+type Buffer struct {
+	MaxLines int
+
+	mu     sync.Mutex
+	blocks []Block
+	n      int
+	lines  int
+	nextID int
+}
+
+func (b *Buffer) push(bl Block) {
+	bl.ID = b.nextID
+	b.nextID++
+	b.blocks = append(b.blocks, bl)
+	b.lines += bl.lines
+}
+
+// trim 스크롤백 제한. 마지막 블록은 남긴다.
+func (b *Buffer) trim() {
+	if b.MaxLines <= 0 {
+		return
+	}
+	drop := 0
+	for drop < len(b.blocks)-1 && b.lines > b.MaxLines {
+		b.lines -= b.blocks[drop].lines
+		drop++
+	}
+	if drop > 0 {
+		b.blocks = append([]Block(nil), b.blocks[drop:]...)
+	}
+}
+
+func (b *Buffer) Write(p []byte) (int, error) {
+	return b.WriteString(string(p))
+}
+
+func (b *Buffer) WriteString(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	nl := strings.Count(s, "\n")
+	last := len(b.blocks) - 1
+	if last >= 0 && b.blocks[last].Table == nil &&
+		(b.blocks[last].lines < chunkLines || !strings.HasSuffix(b.blocks[last].Text, "\n")) {
+		b.blocks[last].Text += s
+		b.blocks[last].lines += nl
+		b.lines += nl
+	} else {
+		b.push(Block{Text: s, lines: nl})
+	}
+	b.n += len(s)
+	b.trim()
+	return len(s), nil
+}
+
+func (b *Buffer) WriteByte(ch byte) error {
+	_, err := b.WriteString(string(ch))
+	return err
+}
+
+func (b *Buffer) WriteTable(t *Table) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.push(Block{Table: t, lines: len(t.Rows) + 1})
+	b.trim()
+	// 표도 출력이 있었던 것으로 센다 (RunScript 줄바꿈 판단)
+	b.n++
+	return nil
+}
+
+// EndLine 마지막 텍스트가 줄 중간에서 끝났으면 줄바꿈
+func (b *Buffer) EndLine() {
+	b.mu.Lock()
+	last := len(b.blocks) - 1
+	open := last >= 0 && b.blocks[last].Table == nil && !strings.HasSuffix(b.blocks[last].Text, "\n")
+	b.mu.Unlock()
+	if open {
+		_ = b.WriteByte('\n')
+	}
+}
+
+// Len 지금까지 쓴 양. 텍스트는 바이트 수, 표는 1로 센다.
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.n
+}
+
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blocks = nil
+	b.n = 0
+	b.lines = 0
+}
+
+// Blocks 현재 블록의 복사본
+func (b *Buffer) Blocks() []Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Block(nil), b.blocks...)
+}
+
+// String 표는 TSV로 바꾼 전체 텍스트
+func (b *Buffer) String() string {
+	var sb strings.Builder
+	_ = writeBlocks(&sb, b.Blocks(), formatTSV)
+	return sb.String()
+}
+
+type tableFormat int
+
+const (
+	formatTSV tableFormat = iota
+	formatJSON
+)
+
+// writeBlocks 블록을 평문으로. 표 앞뒤로 줄이 바뀌도록 한다.
+func writeBlocks(w io.Writer, blocks []Block, format tableFormat) error {
+	atLineStart := true
+	for _, bl := range blocks {
+		if bl.Table == nil {
+			if _, err := io.WriteString(w, bl.Text); err != nil {
+				return err
+			}
+			atLineStart = strings.HasSuffix(bl.Text, "\n")
+			continue
+		}
+
+		if !atLineStart {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		var err error
+		if format == formatJSON {
+			err = bl.Table.WriteJSON(w)
+		} else {
+			err = bl.Table.WriteTSV(w)
+		}
+		if err != nil {
+			return err
+		}
+		atLineStart = true
+	}
+	return nil
+}
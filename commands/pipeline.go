@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+var (
+	cmdJSON = Cmd{
+		Name:  "json",
+		Usage: "<command> | json",
+		Exec: func(_ context.Context, c *Context, _ []string) error {
+			return handleConvert(c, "json", formatJSON)
+		},
+	}
+
+	cmdTSV = Cmd{
+		Name:  "tsv",
+		Usage: "<command> | tsv",
+		Exec: func(_ context.Context, c *Context, _ []string) error {
+			return handleConvert(c, "tsv", formatTSV)
+		},
+	}
+)
+
+// pipeline "a | b > file" 을 나눈 결과
+type pipeline struct {
+	stages   []string
+	target   string // 리다이렉트 대상 (토큰화 전)
+	appendTo bool   // >>
+}
+
+// splitPipeline 따옴표, \, !(a|b) 밖의 |, >, >> 로 명령줄을 나눈다.
+// 리다이렉트는 맨 끝에 하나만 올 수 있다.
+func splitPipeline(line string) (pipeline, error) {
+	var (
+		p      pipeline
+		cur    strings.Builder
+		depth  int // !( ... ) 안
+		redir  bool
+		quote  byte
+		unexpt = func(tok string) error {
+			return fmt.Errorf("syntax error near unexpected token `%s'", tok)
+		}
+	)
+
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' && i+1 < len(line) {
+				cur.WriteByte(ch)
+				i++
+				ch = line[i]
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\\' && i+1 < len(line):
+			cur.WriteByte(ch)
+			i++
+			ch = line[i]
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(' && (depth > 0 || (i > 0 && line[i-1] == '!')):
+			depth++
+		case ch == ')' && depth > 0:
+			depth--
+		case depth == 0 && ch == '|':
+			if redir {
+				return p, unexpt("|")
+			}
+			if strings.TrimSpace(cur.String()) == "" {
+				return p, unexpt("|")
+			}
+			p.stages = append(p.stages, cur.String())
+			cur.Reset()
+			continue
+		case depth == 0 && ch == '>':
+			op := ">"
+			if i+1 < len(line) && line[i+1] == '>' {
+				op = ">>"
+				i++
+			}
+			if redir || strings.TrimSpace(cur.String()) == "" {
+				return p, unexpt(op)
+			}
+			p.stages = append(p.stages, cur.String())
+			cur.Reset()
+			p.appendTo = op == ">>"
+			redir = true
+			continue
+		}
+		cur.WriteByte(ch)
+	}
+
+	if !redir {
+		if strings.TrimSpace(cur.String()) == "" && len(p.stages) > 0 {
+			return p, unexpt("|")
+		}
+		p.stages = append(p.stages, cur.String())
+		return p, nil
+	}
+	if strings.TrimSpace(cur.String()) == "" {
+		return p, unexpt("newline")
+	}
+	p.target = cur.String()
+	return p, nil
+}
+
+// runPipeline 앞 단계 출력을 다음 단계의 입력(stdin)으로 넘기고,
+// 마지막 출력은 콘솔이나 리다이렉트 파일로 보낸다.
+func runPipeline(ctx context.Context, c *Context, p pipeline) error {
+	console, stdin := c.ConsoleBuf, c.stdin
+	defer func() {
+		c.ConsoleBuf, c.stdin = console, stdin
+	}()
+
+	var in *Buffer
+	for i, stage := range p.stages {
+		last := i == len(p.stages)-1
+		c.stdin = in
+		if last && p.target == "" {
+			c.ConsoleBuf = console
+		} else {
+			in = &Buffer{}
+			c.ConsoleBuf = in
+		}
+		if err := callWords(ctx, c, stage); err != nil {
+			return err
+		}
+	}
+
+	if p.target == "" {
+		return nil
+	}
+	c.ConsoleBuf = console
+	return writeRedirect(c, p, in)
+}
+
+// writeRedirect 표는 .json 파일이면 JSON, 그 외에는 TSV로 쓴다
+func writeRedirect(c *Context, p pipeline, out *Buffer) error {
+	words, err := tokenize(p.target, c.lookupVar)
+	if err != nil {
+		return err
+	}
+	if len(words) != 1 {
+		return fmt.Errorf("%s: ambiguous redirect", strings.TrimSpace(p.target))
+	}
+	fp, err := resolvePath(c, words[0])
+	if err != nil {
+		return err
+	}
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if p.appendTo {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := c.fsys().OpenFile(fp, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func(f vfs.File) {
+		fErr := f.Close()
+		if fErr != nil {
+			c.Logger.Error("failed close file", "file", fp, "err", fErr)
+		}
+	}(f)
+
+	format := formatTSV
+	if strings.EqualFold(filepath.Ext(fp), ".json") {
+		format = formatJSON
+	}
+	blocks := out.Blocks()
+	// 파일에는 색 없이
+	var sb strings.Builder
+	if err = writeBlocks(&sb, blocks, format); err != nil {
+		return err
+	}
+	if _, err = io.WriteString(f, StripANSI(sb.String())); err != nil {
+		return err
+	}
+	// 마지막 줄바꿈
+	if n := len(blocks); n > 0 && blocks[n-1].Table == nil && !strings.HasSuffix(blocks[n-1].Text, "\n") {
+		_, err = io.WriteString(f, "\n")
+	}
+	return err
+}
+
+// handleConvert 파이프로 받은 표를 글자로 바꿔 출력
+func handleConvert(c *Context, name string, format tableFormat) error {
+	if c.stdin == nil {
+		return errors.New(name + ": no input (use with a pipe)")
+	}
+	var sb strings.Builder
+	if err := writeBlocks(&sb, c.stdin.Blocks(), format); err != nil {
+		return err
+	}
+	_, err := c.ConsoleBuf.WriteString(strings.TrimSuffix(StripANSI(sb.String()), "\n"))
+	return err
+}
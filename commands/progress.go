@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+// progressInterval 진행 상황을 알리는 간격. 이보다 빨리 끝나는 복사는 알리지 않는다.
+const progressInterval = 200 * time.Millisecond
+
+// Transfer 파일 하나의 복사 진행 상황 (cp, 다른 FS 사이의 mv)
+type Transfer struct {
+	Src   string
+	Dst   string
+	Done  int64
+	Total int64 // 모르면 -1
+	End   bool  // 끝났다 (성공, 실패, 취소 모두)
+}
+
+// Fraction 0~1. Total을 모르면 -1
+func (t Transfer) Fraction() float64 {
+	if t.Total <= 0 {
+		return -1
+	}
+	return min(float64(t.Done)/float64(t.Total), 1)
+}
+
+func (t Transfer) String() string {
+	name := filepath.Base(t.Src)
+	if t.Total < 0 {
+		return fmt.Sprintf("%s %s", name, humanSize(t.Done))
+	}
+	return fmt.Sprintf("%s %s / %s (%d%%)", name, humanSize(t.Done), humanSize(t.Total), int(t.Fraction()*100))
+}
+
+// tracker 읽은 양을 progressInterval마다 Context.Progress로.
+// 여러 파일을 하나의 Transfer로 묶을 수도 있다 (zip, tar). Progress가 없으면 nil.
+type tracker struct {
+	report func(Transfer)
+	t      Transfer
+	last   time.Time
+	shown  bool
+}
+
+func (c *Context) newTracker(src, dst string, total int64) *tracker {
+	if c.Progress == nil {
+		return nil
+	}
+	return &tracker{
+		report: c.Progress,
+		t:      Transfer{Src: src, Dst: dst, Total: total},
+		last:   time.Now(),
+	}
+}
+
+// trackTransfer 파일 하나. 끝나면 end를 불러야 한다.
+func (c *Context) trackTransfer(r io.Reader, src, dst string, total int64) (io.Reader, func()) {
+	k := c.newTracker(src, dst, total)
+	return k.reader(r), k.end
+}
+
+// reader r에서 읽은 만큼 센다
+func (k *tracker) reader(r io.Reader) io.Reader {
+	if k == nil {
+		return r
+	}
+	return &progressReader{r: r, k: k}
+}
+
+func (k *tracker) add(n int) {
+	k.t.Done += int64(n)
+	if now := time.Now(); now.Sub(k.last) >= progressInterval {
+		k.last, k.shown = now, true
+		k.report(k.t)
+	}
+}
+
+// end 알린 적이 있을 때만 끝을 알린다
+func (k *tracker) end() {
+	if k != nil && k.shown {
+		k.t.End = true
+		k.report(k.t)
+	}
+}
+
+type progressReader struct {
+	r io.Reader
+	k *tracker
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.k.add(n)
+	return n, err
+}
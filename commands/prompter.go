@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Prompter 명령 실행 중 사용자에게 묻기 (덮어쓰기, 삭제 확인, 디렉터리 고르기).
+// 명령 고루틴에서 호출하고, 답을 기다리는 동안 ctx가 취소되면 ctx.Err()를 돌려준다.
+type Prompter interface {
+	Confirm(ctx context.Context, q Question) (bool, error)
+	// Choose 목록에서 하나. 고르지 않고 닫으면 ""
+	Choose(ctx context.Context, title string, options []string) (string, error)
+}
+
+// Question 예/아니오 질문. Yes/No는 버튼 이름 (비어 있으면 "yes"/"no")
+type Question struct {
+	Title   string
+	Message string
+	Yes     string
+	No      string
+}
+
+func (q Question) yes() string {
+	if q.Yes == "" {
+		return "yes"
+	}
+	return q.Yes
+}
+
+func (q Question) no() string {
+	if q.No == "" {
+		return "no"
+	}
+	return q.No
+}
+
+// errNoPrompter 창도 Prompter도 없이 사용자 확인이 필요할 때
+var errNoPrompter = errors.New("confirmation required but no prompter is available")
+
+// prompter Context.Prompter, 없으면 창의 다이얼로그
+func (c *Context) prompter() (Prompter, error) {
+	if c.Prompter != nil {
+		return c.Prompter, nil
+	}
+	if c.Window != nil {
+		return FynePrompter{Window: c.Window}, nil
+	}
+	return nil, errNoPrompter
+}
+
+// FynePrompter 창 위의 모달 다이얼로그로 묻는다
+type FynePrompter struct {
+	Window fyne.Window
+}
+
+func (p FynePrompter) Confirm(ctx context.Context, q Question) (bool, error) {
+	// 2-버튼 모달로 물어보기 (UI 스레드에서 생성)
+	ch := make(chan string, 1)
+	var dd dialog.Dialog
+
+	fyne.Do(func() {
+		msg := widget.NewLabel(q.Message)
+		btnNo := widget.NewButton(q.no(), func() { ch <- q.no(); dd.Hide() })
+		btnYes := widget.NewButton(q.yes(), func() { ch <- q.yes(); dd.Hide() })
+
+		grid := container.NewGridWithColumns(2, btnNo, btnYes)
+		content := container.NewVBox(msg, widget.NewSeparator(), grid)
+
+		dd = dialog.NewCustomWithoutButtons(q.Title, content, p.Window)
+		dd.Show()
+	})
+
+	// 백그라운드(현재 고루틴)에서 사용자 선택 대기
+	v, err := awaitChoice(ctx, ch, func() { dd.Hide() })
+	return v == q.yes(), err
+}
+
+func (p FynePrompter) Choose(ctx context.Context, title string, options []string) (string, error) {
+	ch := make(chan string, 1)
+	var dd dialog.Dialog
+
+	fyne.Do(func() {
+		list := widget.NewList(
+			func() int { return len(options) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(id widget.ListItemID, o fyne.CanvasObject) {
+				o.(*widget.Label).SetText(options[id])
+			},
+		)
+		list.OnSelected = func(id widget.ListItemID) {
+			ch <- options[id]
+			dd.Hide()
+		}
+
+		dd = dialog.NewCustom(title, "cancel", list, p.Window)
+		dd.SetOnClosed(func() {
+			// 목록에서 고르지 않고 닫으면 취소
+			select {
+			case ch <- "":
+			default:
+			}
+		})
+		dd.Resize(fyne.NewSize(480, 320))
+		dd.Show()
+	})
+
+	return awaitChoice(ctx, ch, func() { dd.Hide() })
+}
+
+// TerminalPrompter 질문은 Out에, 답은 In에서 한 줄씩 (minder exec/repl).
+// 입력이 끝났으면 no, 또는 취소로 본다.
+type TerminalPrompter struct {
+	In  *bufio.Reader
+	Out io.Writer
+}
+
+func (p TerminalPrompter) Confirm(ctx context.Context, q Question) (bool, error) {
+	_, _ = fmt.Fprintf(p.Out, "%s [y/N] ", q.Message)
+	line, err := p.readLine(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(line) {
+	case "y", "yes", strings.ToLower(q.yes()):
+		return true, nil
+	}
+	return false, nil
+}
+
+func (p TerminalPrompter) Choose(ctx context.Context, title string, options []string) (string, error) {
+	_, _ = fmt.Fprintln(p.Out, title)
+	for i, o := range options {
+		_, _ = fmt.Fprintf(p.Out, "  %d) %s\n", i+1, o)
+	}
+	for {
+		_, _ = fmt.Fprintf(p.Out, "choose 1-%d (empty to cancel): ", len(options))
+		line, err := p.readLine(ctx)
+		if err != nil || line == "" {
+			return "", err
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+	}
+}
+
+// readLine 읽는 동안은 취소할 수 없으므로 전후로 ctx를 확인한다
+func (p TerminalPrompter) readLine(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	line, err := p.In.ReadString('\n')
+	if err != nil && line == "" {
+		_, _ = fmt.Fprintln(p.Out)
+		if errors.Is(err, io.EOF) {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), ctx.Err()
+}
+
+// AutoPrompter 묻지 않고 정해진 답 (--yes/--no). Choose는 첫 항목.
+// Out이 있으면 질문과 답을 남긴다.
+type AutoPrompter struct {
+	Answer bool
+	Out    io.Writer
+}
+
+func (p AutoPrompter) Confirm(ctx context.Context, q Question) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if p.Out != nil {
+		reply := q.no()
+		if p.Answer {
+			reply = q.yes()
+		}
+		_, _ = fmt.Fprintf(p.Out, "%s %s\n", q.Message, reply)
+	}
+	return p.Answer, nil
+}
+
+func (p AutoPrompter) Choose(ctx context.Context, _ string, options []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(options) == 0 {
+		return "", nil
+	}
+	return options[0], nil
+}
+
+// ScriptedPrompter 정해 둔 답을 차례로 돌려준다 (테스트용).
+// Confirm은 "y", "yes" 또는 Question.Yes와 같으면 예, Choose는 답을 그대로 (목록에 없으면 오류).
+type ScriptedPrompter struct {
+	mu      sync.Mutex
+	Answers []string
+	Asked   []string // 받은 질문 (Question.Message 또는 Choose의 title)
+}
+
+// errNoAnswer ScriptedPrompter의 답이 모자랄 때
+var errNoAnswer = errors.New("scripted prompter: no answer left")
+
+func (p *ScriptedPrompter) next(asked string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Asked = append(p.Asked, asked)
+	if len(p.Answers) == 0 {
+		return "", errNoAnswer
+	}
+	a := p.Answers[0]
+	p.Answers = p.Answers[1:]
+	return a, nil
+}
+
+func (p *ScriptedPrompter) Confirm(ctx context.Context, q Question) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	a, err := p.next(q.Message)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(a) {
+	case "y", "yes", strings.ToLower(q.yes()):
+		return true, nil
+	}
+	return false, nil
+}
+
+func (p *ScriptedPrompter) Choose(ctx context.Context, title string, options []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	a, err := p.next(title)
+	if err != nil {
+		return "", err
+	}
+	if a != "" && !slices.Contains(options, a) {
+		return "", fmt.Errorf("scripted prompter: %q is not one of %v", a, options)
+	}
+	return a, nil
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+func TestResolveConflict(t *testing.T) {
+	tests := []struct {
+		answers []string
+		want    string
+		wantErr error
+	}{
+		{answers: []string{"overwrite"}, want: "overwrite"},
+		{answers: []string{"y"}, want: "overwrite"},
+		{answers: []string{"skip"}, want: "skip"},
+		{answers: []string{"no"}, want: "skip"},
+		{answers: nil, wantErr: errNoAnswer},
+	}
+	for _, tt := range tests {
+		c, p := newTestContext(vfs.NewMem(), "/", tt.answers...)
+		got, err := resolveConflict(context.Background(), c, "/dst/a.txt")
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("answers %v: got %q, %v; want %q, %v", tt.answers, got, err, tt.want, tt.wantErr)
+		}
+		if len(p.Asked) != 1 {
+			t.Errorf("answers %v: asked %d times", tt.answers, len(p.Asked))
+		}
+	}
+}
+
+// cp의 충돌 질문: 답이 모자라면 errNoAnswer로 멈추고 대상은 그대로
+func TestCopyConflictPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []string
+		wantErr error
+		want    map[string]string
+	}{
+		{
+			name:    "overwrite then skip",
+			answers: []string{"overwrite", "skip"},
+			want:    map[string]string{"/dst/a.txt": "new a", "/dst/b.txt": "old b"},
+		},
+		{
+			name:    "answers run out",
+			answers: []string{"overwrite"},
+			wantErr: errNoAnswer,
+			want:    map[string]string{"/dst/a.txt": "new a", "/dst/b.txt": "old b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := vfs.NewMem()
+			writeFiles(t, fsys, map[string]string{
+				"/work/a.txt": "new a", "/work/b.txt": "new b",
+				"/dst/a.txt": "old a", "/dst/b.txt": "old b",
+			})
+			c, p := newTestContext(fsys, "/work", tt.answers...)
+			err := run(c, "cp a.txt b.txt /dst")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(p.Asked) != 2 {
+				t.Errorf("asked %v", p.Asked)
+			}
+			checkFiles(t, fsys, tt.want)
+		})
+	}
+}
+
+// rm 확인: 지울지 하나씩 묻는다
+func TestRemoveConfirmPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		answers []string
+		wantErr error
+		want    map[string]string
+	}{
+		{
+			name:    "delete and skip",
+			line:    "rm a.txt b.txt",
+			answers: []string{"delete", "skip"},
+			want:    map[string]string{"/work/a.txt": "", "/work/b.txt": "b"},
+		},
+		{
+			name:    "directory",
+			line:    "rm -r d",
+			answers: []string{"yes"},
+			want:    map[string]string{"/work/d/c.txt": ""},
+		},
+		{
+			name:    "answers run out",
+			line:    "rm a.txt b.txt",
+			answers: []string{"y"},
+			wantErr: errNoAnswer,
+			want:    map[string]string{"/work/a.txt": "", "/work/b.txt": "b"},
+		},
+		{
+			name: "force does not ask",
+			line: "rm -f a.txt b.txt",
+			want: map[string]string{"/work/a.txt": "", "/work/b.txt": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := vfs.NewMem()
+			writeFiles(t, fsys, map[string]string{"/work/a.txt": "a", "/work/b.txt": "b", "/work/d/c.txt": "c"})
+			c, p := newTestContext(fsys, "/work", tt.answers...)
+			err := run(c, tt.line)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.answers == nil && len(p.Asked) > 0 {
+				t.Errorf("asked %v", p.Asked)
+			}
+			checkFiles(t, fsys, tt.want)
+		})
+	}
+}
+
+// z: 점수가 비슷한 후보가 여럿이면 고르게 한다
+func TestZChoosePrompt(t *testing.T) {
+	root := t.TempDir()
+	a := filepath.Join(root, "proj", "alpha")
+	b := filepath.Join(root, "proj", "beta")
+	for _, d := range []string{a, b} {
+		if err := vfs.OS.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		answers []string
+		want    string // 바뀐 Pwd
+		wantErr error
+	}{
+		{name: "choose second", answers: []string{b}, want: b},
+		{name: "choose first", answers: []string{a}, want: a},
+		{name: "answers run out", want: root, wantErr: errNoAnswer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// cd도 방문을 기록하므로 경우마다 새 기록 파일
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("AppData", t.TempDir())
+			for _, d := range []string{a, b} {
+				if err := RecordDir(d); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c, p := newTestContext(nil, root, tt.answers...)
+			err := run(c, "z proj")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got, _ := c.Pwd.Get(); got != tt.want {
+				t.Errorf("pwd = %s, want %s", got, tt.want)
+			}
+			if !slices.Equal(p.Asked, []string{"z: choose directory"}) {
+				t.Errorf("asked %v", p.Asked)
+			}
+		})
+	}
+}
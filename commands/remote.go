@@ -0,0 +1,290 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// remotesFile 설정 디렉터리의 원격 저장소 목록 (JSON 배열)
+//
+//	[{"name": "team", "type": "webdav", "url": "https://dav.example.com/assets",
+//	  "username": "me", "password": "secret"},
+//	 {"name": "box", "url": "sftp://me@box.example.com/srv/data"},
+//	 {"name": "builds", "url": "s3://artifacts/ci", "endpoint": "http://localhost:9000",
+//	  "username": "<access key>", "password": "<secret key>"}]
+const remotesFile = "remotes.json"
+
+var (
+	cmdConnect = Cmd{
+		Name: "connect",
+		Args: []string{"[<name>|<url>]"},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			switch len(args) {
+			case 0:
+				return handleRemotes(c)
+			case 1:
+				return handleConnect(ctx, c, args[0])
+			}
+			return usageError("connect: too many arguments")
+		},
+	}
+
+	cmdDisconnect = Cmd{
+		Name: "disconnect",
+		Args: []string{"<name>|<mount>"},
+		Exec: func(_ context.Context, c *Context, args []string) error {
+			if len(args) != 1 {
+				return usageError("disconnect: missing argument")
+			}
+			return handleDisconnect(c, args[0])
+		},
+	}
+)
+
+// Remote 원격 저장소 하나. 연결하면 Mount(없으면 /@name)에 붙는다.
+type Remote struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // webdav, sftp, s3 (비어 있으면 URL로 판단)
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"` // s3: access key
+	Password string `json:"password,omitempty"` // s3: secret key
+	Mount    string `json:"mount,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"` // s3: S3 호환 서버 주소 (없으면 AWS)
+	Region   string `json:"region,omitempty"`   // s3
+}
+
+func (r Remote) MountPoint() string {
+	if r.Mount != "" {
+		return filepath.Clean(r.Mount)
+	}
+	return vfs.MountPoint(r.Name)
+}
+
+func (r Remote) kind() string {
+	if r.Type != "" {
+		return strings.ToLower(r.Type)
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return "webdav"
+	case "sftp", "s3":
+		return u.Scheme
+	}
+	return ""
+}
+
+// Dial 원격 FS를 만들고 루트가 보이는지 확인한다 (주소, 인증 오류는 여기서).
+// ctx가 끝나면(Ctrl+C, timeout) 확인을 멈춘다
+func (r Remote) Dial(ctx context.Context) (vfs.FS, error) {
+	var fsys vfs.FS
+	switch r.kind() {
+	case "webdav":
+		dav, err := vfs.NewWebDAV(r.URL, vfs.WebDAVOptions{Username: r.Username, Password: r.Password})
+		if err != nil {
+			return nil, err
+		}
+		fsys = dav
+	case "sftp":
+		rawURL := r.URL
+		if u, err := url.Parse(r.URL); err == nil && u.User == nil && r.Username != "" {
+			u.User = url.User(r.Username)
+			rawURL = u.String()
+		}
+		s, err := vfs.DialSFTP(ctx, rawURL, vfs.SFTPOptions{Password: r.Password})
+		if err != nil {
+			return nil, err
+		}
+		fsys = s
+	case "s3":
+		s, err := vfs.NewS3(r.URL, vfs.S3Options{
+			Endpoint:  r.Endpoint,
+			Region:    r.Region,
+			AccessKey: r.Username,
+			SecretKey: r.Password,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fsys = s
+	default:
+		return nil, fmt.Errorf("remote %q: unsupported type %q", r.Name, r.Type)
+	}
+
+	fi, err := vfs.WithContext(ctx, fsys).Stat(string(filepath.Separator))
+	if err != nil {
+		if c, ok := fsys.(io.Closer); ok {
+			_ = c.Close()
+		}
+		return nil, fmt.Errorf("remote %q: %w", r.Name, err)
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("remote %q: %s is not a directory", r.Name, r.URL)
+	}
+	return fsys, nil
+}
+
+// LoadRemotes 설정 파일의 원격 저장소들. 파일이 없으면 nil
+func LoadRemotes() ([]Remote, error) {
+	fp, err := configPath(remotesFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fp)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []Remote
+	if err = json.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("%s: %w", fp, err)
+	}
+	for i, r := range rs {
+		if r.Name == "" || r.URL == "" {
+			return nil, fmt.Errorf("%s: remote #%d needs name and url", fp, i+1)
+		}
+	}
+	return rs, nil
+}
+
+// findRemote 이름이나 URL로. 설정에 없는 URL은 인증 없이 호스트 이름으로 붙인다.
+func findRemote(arg string) (Remote, error) {
+	rs, err := LoadRemotes()
+	if err != nil {
+		return Remote{}, err
+	}
+	for _, r := range rs {
+		if r.Name == arg || r.URL == arg {
+			return r, nil
+		}
+	}
+	if u, err := url.Parse(arg); err == nil && u.Scheme != "" && u.Host != "" {
+		return Remote{Name: u.Hostname(), URL: arg}, nil
+	}
+	return Remote{}, fmt.Errorf("unknown remote %q", arg)
+}
+
+// Connect r을 mux에 붙이고 마운트 지점을 돌려준다. 이미 붙어 있으면 그대로.
+func Connect(ctx context.Context, mux *vfs.Mux, r Remote) (string, error) {
+	dir := r.MountPoint()
+	for _, m := range mux.Mounts() {
+		if m.Dir == dir {
+			return dir, nil
+		}
+	}
+	fsys, err := r.Dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	// 원격의 아카이브도 안을 볼 수 있게
+	if err = mux.Mount(dir, vfs.NewArchives(fsys)); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// mux 마운트를 지원하는 FS인지
+func (c *Context) mux() (*vfs.Mux, error) {
+	mux, ok := c.fsys().(*vfs.Mux)
+	if !ok {
+		return nil, errors.New("remote filesystems are not available here")
+	}
+	return mux, nil
+}
+
+func handleConnect(ctx context.Context, c *Context, arg string) error {
+	mux, err := c.mux()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	r, err := findRemote(arg)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	dir, err := Connect(ctx, mux, r)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	c.refreshSideBar()
+	_, err = fmt.Fprintf(c.ConsoleBuf, "connect: %s → %s", r.URL, dirText(dir))
+	return err
+}
+
+func handleDisconnect(c *Context, arg string) error {
+	mux, err := c.mux()
+	if err != nil {
+		return fmt.Errorf("disconnect: %w", err)
+	}
+	dir := vfs.MountPoint(arg)
+	if filepath.IsAbs(arg) {
+		dir = filepath.Clean(arg)
+	} else if r, err := findRemote(arg); err == nil {
+		dir = r.MountPoint()
+	}
+	if err = mux.Unmount(dir); err != nil {
+		return fmt.Errorf("disconnect: %w", err)
+	}
+
+	// 떼어 낸 곳에 있었으면 홈으로
+	if pwd, _ := c.Pwd.Get(); pwd == dir || isSubpath(pwd, dir) {
+		if home, err := os.UserHomeDir(); err == nil {
+			if err = changeDir(c, home); err != nil {
+				return err
+			}
+		}
+	}
+	c.refreshSideBar()
+	_, err = fmt.Fprintf(c.ConsoleBuf, "disconnect: %s", dir)
+	return err
+}
+
+// handleRemotes 설정된 원격 저장소와 연결 상태
+func handleRemotes(c *Context) error {
+	rs, err := LoadRemotes()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	mounted := map[string]bool{}
+	if mux, err := c.mux(); err == nil {
+		for _, m := range mux.Mounts() {
+			mounted[m.Dir] = true
+		}
+	}
+
+	if len(rs) == 0 {
+		fp, _ := configPath(remotesFile)
+		_, err = fmt.Fprintf(c.ConsoleBuf, "no remotes configured (%s)", fp)
+		return err
+	}
+	t := NewTable(
+		Column{Name: "name", Kind: ColText},
+		Column{Name: "type", Kind: ColText},
+		Column{Name: "url", Kind: ColText},
+		Column{Name: "mount", Kind: ColPath},
+		Column{Name: "status", Kind: ColText},
+	)
+	for _, r := range rs {
+		if mounted[r.MountPoint()] {
+			t.AppendDir(r.Name, r.kind(), r.URL, r.MountPoint(), "connected")
+			continue
+		}
+		t.Append(r.Name, r.kind(), r.URL, r.MountPoint(), "")
+	}
+	return c.ConsoleBuf.WriteTable(t)
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// 응답하지 않는 서버에 connect해도 timeout(Ctrl+C)으로 멈춘다
+func TestConnectCanceled(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+
+	stop := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer srv.Close()
+	defer close(stop)
+
+	mux := vfs.NewMux(vfs.NewMem())
+	c, _ := newTestContext(mux, "/")
+	start := time.Now()
+	err := run(c, "timeout 0.2 connect "+srv.URL+"/")
+	if ExitCode(err) != StatusTimeout {
+		t.Errorf("err = %v, want timeout", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("connect took %s", d)
+	}
+	if len(mux.Mounts()) != 0 {
+		t.Errorf("mounted %v", mux.Mounts())
+	}
+}
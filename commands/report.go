@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+var cmdRetry = Cmd{
+	Name:  "retry",
+	Usage: "retry",
+	Exec: func(ctx context.Context, c *Context, _ []string) error {
+		return handleRetry(ctx, c)
+	},
+}
+
+// retryFunc 실패한 경로 하나를 다시 처리한다. c에는 새 report가 붙어 있다.
+type retryFunc func(ctx context.Context, c *Context) error
+
+type entryStatus int
+
+const (
+	entryOK entryStatus = iota
+	entrySkipped
+	entryFailed
+)
+
+func (s entryStatus) String() string {
+	switch s {
+	case entrySkipped:
+		return "skipped"
+	case entryFailed:
+		return "failed"
+	default:
+		return "ok"
+	}
+}
+
+func statusText(s entryStatus) string {
+	switch s {
+	case entrySkipped:
+		return warnText(s.String())
+	case entryFailed:
+		return ErrorText(s.String())
+	default:
+		return okText(s.String())
+	}
+}
+
+type reportEntry struct {
+	path   string
+	status entryStatus
+	reason string
+	retry  retryFunc
+}
+
+// opReport -k(keep going)로 실행한 cp/mv/rm의 경로별 결과
+// 성공은 개수만 세고, 건너뜀/실패만 사유와 함께 보관한다.
+// nil이면 keep going이 아닌 것으로 보고 오류를 그대로 돌려준다.
+type opReport struct {
+	name string
+
+	mu        sync.Mutex
+	succeeded int
+	entries   []reportEntry
+}
+
+func newReport(name string) *opReport {
+	return &opReport{name: name}
+}
+
+func (r *opReport) succeed() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.succeeded++
+	r.mu.Unlock()
+}
+
+func (r *opReport) skip(path, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.entries = append(r.entries, reportEntry{path: path, status: entrySkipped, reason: reason})
+	r.mu.Unlock()
+}
+
+// skip 건너뛴 경로를 세고 report에 남긴다
+func (c *Context) skip(path, reason string) {
+	c.skipped++
+	c.report.skip(path, reason)
+}
+
+// fail 실패를 기록하고 nil을 돌려줘 호출한 쪽이 다음 경로로 넘어가게 한다.
+// report가 없으면(keep going 아님) err를 그대로 돌려준다.
+// 취소(Ctrl+C, 창 닫기, timeout)는 기록하지 않고 그대로 돌려줘 작업 전체를 멈춘다.
+func (r *opReport) fail(path string, err error, retry retryFunc) error {
+	if r == nil || err == nil || isCanceled(err) {
+		return err
+	}
+	r.mu.Lock()
+	r.entries = append(r.entries, reportEntry{path: path, status: entryFailed, reason: err.Error(), retry: retry})
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *opReport) failures() []reportEntry {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []reportEntry
+	for _, e := range r.entries {
+		if e.status == entryFailed {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (r *opReport) failedCount() int {
+	return len(r.failures())
+}
+
+// write 요약 표: 개수 + 건너뜀/실패 항목과 사유
+func (r *opReport) write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skipped, failed := 0, 0
+	for _, e := range r.entries {
+		if e.status == entrySkipped {
+			skipped++
+		} else {
+			failed++
+		}
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%s: %d succeeded, %d skipped, %d failed\n", r.name, r.succeeded, skipped, failed)
+	if len(r.entries) > 0 {
+		var tb strings.Builder
+		tw := tabwriter.NewWriter(&tb, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "STATUS\tPATH\tREASON")
+		for _, e := range r.entries {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", e.status, e.path, e.reason)
+		}
+		_ = tw.Flush()
+
+		// 정렬이 끝난 뒤 상태 글자에만 색 (이스케이프가 칸 너비에 들어가지 않도록)
+		lines := strings.SplitAfter(tb.String(), "\n")
+		for i, e := range r.entries {
+			lines[i+1] = statusText(e.status) + strings.TrimPrefix(lines[i+1], e.status.String())
+		}
+		sb.WriteString(strings.Join(lines, ""))
+	}
+	if failed > 0 {
+		_, _ = fmt.Fprint(&sb, warnText(fmt.Sprintf("run `retry` to retry %d failed entries", failed)))
+	}
+
+	_, err := io.WriteString(w, strings.TrimSuffix(sb.String(), "\n"))
+	return err
+}
+
+// err 실패가 있으면 명령 결과도 실패로
+func (r *opReport) err() error {
+	if n := r.failedCount(); n > 0 {
+		return fmt.Errorf("%s: %d entries failed", r.name, n)
+	}
+	return nil
+}
+
+// withReport 이번 호출에서만 쓰는 report를 붙인 Context 복사본
+func (c *Context) withReport(r *opReport) *Context {
+	cc := *c
+	cc.report = r
+	return &cc
+}
+
+// finishReport 요약을 출력하고 retry 대상으로 보관한다
+func finishReport(c *Context, r *opReport) error {
+	c.lastReport = r
+	if err := r.write(c.ConsoleBuf); err != nil {
+		return err
+	}
+	return r.err()
+}
+
+func handleRetry(ctx context.Context, c *Context) error {
+	failed := c.lastReport.failures()
+	if len(failed) == 0 {
+		return errors.New("retry: nothing to retry")
+	}
+
+	rep := newReport("retry")
+	sub := c.withReport(rep)
+	for _, e := range failed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if e.retry == nil {
+			_ = rep.fail(e.path, errors.New(e.reason), nil)
+			continue
+		}
+		if err := rep.fail(e.path, e.retry(ctx, sub), e.retry); err != nil {
+			return err
+		}
+	}
+
+	c.refreshSideBar()
+	return finishReport(c, rep)
+}
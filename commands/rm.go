@@ -1,26 +1,36 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/widget"
+	"github.com/meteormin/minder/vfs"
 )
 
 var cmdRm = Cmd{
 	Name: "rm",
-	Args: []string{"<src>", "<dst>"},
-	Exec: func(c *Context, args []string) error {
-		if len(args) == 0 {
-			return errors.New("rm: missing argument")
+	Args: []string{"[-rfk]", "<path>..."},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		opts, paths, err := getopt("rm", args, "rRfk")
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			if opts.has('f') {
+				return nil
+			}
+			return usageError("rm: missing argument")
 		}
-		return handleRemove(c, args[0])
+		paths, literal, err := expandBraceArgs(paths, c.argLiteral(paths))
+		if err != nil {
+			return fmt.Errorf("rm: %w", err)
+		}
+		return handleRemove(ctx, c, paths, literal, opts.has('r') || opts.has('R'), opts.has('f'), opts.has('k'))
 	},
 }
 
@@ -33,26 +43,52 @@ const (
 )
 
 type remover struct {
-	window fyne.Window
-	logger *slog.Logger
-	mode   rmMode // 사용자가 "모두" 선택 시 상태 고정
+	ctx      context.Context // 취소되면 남은 경로는 건드리지 않는다
+	fsys     vfs.FS
+	prompter Prompter // 없으면 확인이 필요한 삭제는 실패
+	logger   *slog.Logger
+	mode     rmMode // 사용자가 "모두" 선택 시 상태 고정
+
+	recursive bool // -r: 디렉터리 삭제 허용
+	force     bool // -f: 확인 없이 삭제, 없는 경로 무시
+
+	report *opReport // -k: 실패해도 계속, 경로별 결과 기록
+}
+
+// retry 실패한 경로 재시도용. 옵션은 그대로, report만 새 것으로
+func (r *remover) retry(path string, literal bool) retryFunc {
+	return func(ctx context.Context, c *Context) error {
+		rr := *r
+		rr.ctx = ctx
+		rr.report = c.report
+		return rr.removeEntry(path, literal)
+	}
 }
 
-// removeEntry: 패턴/"/." 처리하는 엔트리(래퍼)
-func (r *remover) removeEntry(srcSpec string) error {
+// removeEntry: 패턴/"/." 처리하는 엔트리(래퍼). literal이면 글롭 확장하지 않는다
+func (r *remover) removeEntry(srcSpec string, literal bool) error {
 	// 1) "aDir/." → 내용만 삭제
 	if dir, ok := asDotContents(srcSpec); ok {
 		return r.removeDirContents(dir)
 	}
 
-	// 2) 글롭 확장 (*, ?, [])
-	srcs, err := expandPattern(srcSpec)
-	if err != nil {
-		return err
+	// 2) 글롭 확장 (*, ?, [], **, {}, !()). 따옴표로 감싼 경로는 그대로
+	srcs := []string{srcSpec}
+	if !literal {
+		var err error
+		if srcs, err = expandPattern(r.ctx, r.fsys, srcSpec); err != nil {
+			if r.force {
+				return nil
+			}
+			return err
+		}
 	}
 
 	// 3) 각각 삭제
 	for _, s := range srcs {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
 		// 글롭 결과에 "/."가 남아있을 가능성은 거의 없지만, 안전상 한 번 더 체크
 		if dir, ok := asDotContents(s); ok {
 			if err := r.removeDirContents(dir); err != nil {
@@ -60,7 +96,7 @@ func (r *remover) removeEntry(srcSpec string) error {
 			}
 			continue
 		}
-		if err := r.remove(s); err != nil {
+		if err := r.report.fail(s, r.remove(s), r.retry(s, true)); err != nil {
 			return err
 		}
 	}
@@ -73,11 +109,17 @@ func (r *remover) remove(path string) error {
 		return fmt.Errorf("refuse to remove dangerous path: %s", path)
 	}
 
-	fi, err := os.Lstat(path)
+	fi, err := r.fsys.Lstat(path)
 	if err != nil {
-		// 이미 없음 → rm 기본 동작처럼 에러로 돌려줌(원하면 무시 가능)
+		// 이미 없음 → rm 기본 동작처럼 에러로 돌려줌(-f면 무시)
+		if r.force && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
 		return err
 	}
+	if fi.IsDir() && !r.recursive {
+		return fmt.Errorf("rm: cannot remove '%s': Is a directory", path)
+	}
 
 	// 사용자 확인(모드에 따라 묻지 않거나/한 번만 모두 적용)
 	action, err := r.resolveRemoveConfirm(path, fi.IsDir())
@@ -85,13 +127,57 @@ func (r *remover) remove(path string) error {
 		return err
 	}
 	if action == "skip" {
+		r.report.skip(path, "skipped by user")
 		return nil
 	}
 
 	if fi.IsDir() {
-		return os.RemoveAll(path)
+		if r.report != nil {
+			return r.removeTree(path)
+		}
+		err = r.fsys.RemoveAll(path)
+	} else {
+		err = r.fsys.Remove(path)
+	}
+	if err != nil {
+		return err
+	}
+	r.report.succeed()
+	return nil
+}
+
+// removeTree -k용 RemoveAll: 하위부터 하나씩 지우며 실패한 경로만 기록하고 계속한다
+func (r *remover) removeTree(dir string) error {
+	ents, err := r.fsys.ReadDir(dir)
+	if err != nil {
+		return r.report.fail(dir, err, r.retry(dir, true))
+	}
+	for _, e := range ents {
+		if err = r.ctx.Err(); err != nil {
+			return err
+		}
+		p := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			if err = r.removeTree(p); isCanceled(err) {
+				return err
+			}
+			continue
+		}
+		if err = r.fsys.Remove(p); err != nil {
+			_ = r.report.fail(p, err, r.retry(p, true))
+			continue
+		}
+		r.report.succeed()
+	}
+	if err = r.fsys.Remove(dir); err != nil {
+		// 하위 실패로 비어있지 않은 경우는 이미 기록됨
+		if len(ents) == 0 || !isNotEmpty(err) {
+			return r.report.fail(dir, err, r.retry(dir, true))
+		}
+		return nil
 	}
-	return os.Remove(path)
+	r.report.succeed()
+	return nil
 }
 
 // removeDirContents: 디렉터리의 "내용만" 삭제 (디렉터리 자신은 보존)
@@ -99,13 +185,16 @@ func (r *remover) removeDirContents(dir string) error {
 	if isDangerousRoot(dir) {
 		return fmt.Errorf("refuse to clear dangerous dir: %s", dir)
 	}
-	ents, err := os.ReadDir(dir)
+	ents, err := r.fsys.ReadDir(dir)
 	if err != nil {
 		return err
 	}
 	for _, e := range ents {
+		if err = r.ctx.Err(); err != nil {
+			return err
+		}
 		p := filepath.Join(dir, e.Name())
-		if err := r.remove(p); err != nil {
+		if err := r.report.fail(p, r.remove(p), r.retry(p, true)); err != nil {
 			return err
 		}
 	}
@@ -113,35 +202,37 @@ func (r *remover) removeDirContents(dir string) error {
 }
 
 func (r *remover) resolveRemoveConfirm(target string, isDir bool) (string, error) {
+	if r.force {
+		return "delete", nil
+	}
+
 	switch r.mode {
 	case rmDeleteAll:
 		return "delete", nil
 	case rmSkipAll:
 		return "skip", nil
 	default:
-		ch := make(chan string, 1)
-		var dd dialog.Dialog
-
-		fyne.Do(func() {
-			kind := "file"
-			if isDir {
-				kind = "directory"
-			}
-
-			title := "Confirm delete"
-			msg := widget.NewLabel(fmt.Sprintf("Delete this %s?\n%s", kind, target))
-
-			btnSkip := widget.NewButton("skip", func() { ch <- "skip"; dd.Hide() })
-			btnDel := widget.NewButton("delete", func() { ch <- "delete"; dd.Hide() })
-
-			grid := container.NewGridWithColumns(2, btnSkip, btnDel)
-			content := container.NewVBox(msg, widget.NewSeparator(), grid)
+		if r.prompter == nil {
+			return "", errNoPrompter
+		}
 
-			dd = dialog.NewCustomWithoutButtons(title, content, r.window)
-			dd.Show()
+		kind := "file"
+		if isDir {
+			kind = "directory"
+		}
+		del, err := r.prompter.Confirm(r.ctx, Question{
+			Title:   "Confirm delete",
+			Message: fmt.Sprintf("Delete this %s?\n%s", kind, target),
+			Yes:     "delete",
+			No:      "skip",
 		})
-
-		return <-ch, nil
+		if err != nil {
+			return "", err
+		}
+		if del {
+			return "delete", nil
+		}
+		return "skip", nil
 	}
 }
 
@@ -162,28 +253,56 @@ func isDangerousRoot(p string) bool {
 	return false
 }
 
-// 시그니처를 handleCopy와 "같이" 맞추기 위해 dst는 무시합니다.
-func handleRemove(c *Context, src string) error {
+// handleRemove literal[i]이면 srcs[i]를 글롭 확장하지 않는다
+func handleRemove(ctx context.Context, c *Context, srcs []string, literal []bool, recursive, force, keepGoing bool) error {
 	logger := c.Logger
 	rm := &remover{
-		window: c.Window,
-		logger: logger,
-		mode:   rmAsk,
+		ctx:       ctx,
+		fsys:      c.fsys(),
+		logger:    logger,
+		mode:      rmAsk,
+		recursive: recursive,
+		force:     force,
 	}
-
-	absSrc, err := pathToAbs(c, src)
-	if err != nil {
-		logger.Error("failed path to abs", "src", src, "err", err)
-		return err
+	// 창도 Prompter도 없으면 nil: 확인이 필요할 때 실패
+	rm.prompter, _ = c.prompter()
+	if keepGoing {
+		rm.report = newReport("rm")
 	}
 
-	// ⚠️ 반드시 고루틴에서 실행하고, 오류 표시는 fyne.Do(dialog...)로
-	if err = rm.removeEntry(absSrc); err != nil {
-		return err
+	var done []string
+	var errs []error
+	for i, src := range srcs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		absSrc, err := resolveOperand(c, src)
+		if err != nil {
+			logger.Error("failed resolve path", "src", src, "err", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		// ⚠️ 반드시 고루틴에서 실행하고, 오류 표시는 fyne.Do(dialog...)로
+		lit := isLiteral(literal, i)
+		if err = rm.report.fail(absSrc, rm.removeEntry(absSrc, lit), rm.retry(absSrc, lit)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		done = append(done, absSrc)
 	}
 
-	c.RefreshSideBar()
+	c.refreshSideBar()
 
-	_, err = fmt.Fprintf(c.ConsoleBuf, "rm: %s", absSrc)
-	return err
+	if rm.report != nil && len(errs) == 0 {
+		return finishReport(c, rm.report)
+	}
+
+	if len(done) > 0 {
+		if _, err := fmt.Fprintf(c.ConsoleBuf, "rm: %s", c.pathsText(done)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
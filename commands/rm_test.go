@@ -0,0 +1,94 @@
+package commands
+
+import "testing"
+
+func TestRemove(t *testing.T) {
+	runFSCases(t, []fsCase{
+		{
+			name:  "force file",
+			files: map[string]string{"/work/a.txt": "a", "/work/b.txt": "b"},
+			line:  "rm -f a.txt",
+			want:  map[string]string{"/work/a.txt": "", "/work/b.txt": "b"},
+		},
+		{
+			name:  "force missing",
+			files: map[string]string{"/work/a.txt": "a"},
+			line:  "rm -f nope.txt",
+		},
+		{
+			name:    "missing",
+			files:   map[string]string{"/work/a.txt": "a"},
+			line:    "rm nope.txt",
+			wantErr: true,
+		},
+		{
+			name:    "dir without -r",
+			files:   map[string]string{"/work/d/a.txt": "a"},
+			line:    "rm -f d",
+			wantErr: true,
+			want:    map[string]string{"/work/d/a.txt": "a"},
+		},
+		{
+			name:  "recursive",
+			files: map[string]string{"/work/d/a.txt": "a", "/work/d/sub/b.txt": "b"},
+			line:  "rm -rf d",
+			want:  map[string]string{"/work/d/a.txt": "", "/work/d/sub/b.txt": ""},
+		},
+		{
+			name:  "contents only",
+			files: map[string]string{"/work/d/a.txt": "a", "/work/d/sub/b.txt": "b"},
+			line:  "rm -rf d/.",
+			want:  map[string]string{"/work/d/a.txt": "", "/work/d/sub/b.txt": ""},
+		},
+		{
+			name:  "glob",
+			files: map[string]string{"/work/a.txt": "a", "/work/b.txt": "b", "/work/c.log": "c"},
+			line:  "rm -f *.txt",
+			want:  map[string]string{"/work/a.txt": "", "/work/b.txt": "", "/work/c.log": "c"},
+		},
+		{
+			name:  "quoted glob",
+			files: map[string]string{"/work/*.txt": "x", "/work/a.txt": "a"},
+			line:  `rm -f "*.txt"`,
+			want:  map[string]string{"/work/*.txt": "", "/work/a.txt": "a"},
+		},
+		{
+			name:  "quoted braces",
+			files: map[string]string{"/work/{a,b}": "x", "/work/a": "a", "/work/b": "b"},
+			line:  `rm -f '{a,b}'`,
+			want:  map[string]string{"/work/{a,b}": "", "/work/a": "a", "/work/b": "b"},
+		},
+		{
+			name:  "quoted and unquoted same pattern",
+			files: map[string]string{"/work/*.log": "x", "/work/a.log": "a", "/work/b.txt": "b"},
+			line:  `rm -f '*.log' *.log`,
+			want:  map[string]string{"/work/*.log": "", "/work/a.log": "", "/work/b.txt": "b"},
+		},
+		{
+			name:  "quoted under timeout",
+			files: map[string]string{"/work/*.txt": "x", "/work/a.txt": "a"},
+			line:  `timeout 5 rm -f "*.txt"`,
+			want:  map[string]string{"/work/*.txt": "", "/work/a.txt": "a"},
+		},
+		{
+			name:  "escaped glob",
+			files: map[string]string{"/work/*.txt": "x", "/work/a.txt": "a"},
+			line:  `rm -f \*.txt`,
+			want:  map[string]string{"/work/*.txt": "", "/work/a.txt": "a"},
+		},
+		{
+			name:    "confirm delete and skip",
+			files:   map[string]string{"/work/a.txt": "a", "/work/b.txt": "b"},
+			line:    "rm a.txt b.txt",
+			answers: []string{"delete", "skip"},
+			want:    map[string]string{"/work/a.txt": "", "/work/b.txt": "b"},
+		},
+		{
+			name:    "keep going",
+			files:   map[string]string{"/work/a.txt": "a", "/work/d/x.txt": "x", "/work/b.txt": "b"},
+			line:    "rm -fk a.txt d b.txt",
+			wantErr: true,
+			want:    map[string]string{"/work/a.txt": "", "/work/d/x.txt": "x", "/work/b.txt": ""},
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+var cmdSaveLog = Cmd{
+	Name: "save-log",
+	Args: []string{"<file>"},
+	Exec: func(_ context.Context, c *Context, args []string) error {
+		if len(args) != 1 {
+			return usageError("save-log: usage: save-log <file>")
+		}
+		return handleSaveLog(c, args[0])
+	},
+}
+
+// handleSaveLog 콘솔에 남아 있는 출력(스크롤백) 전체를 색 없이 파일로
+func handleSaveLog(c *Context, file string) error {
+	fp, err := resolvePath(c, file)
+	if err != nil {
+		return err
+	}
+
+	text := StripANSI(c.ConsoleBuf.String())
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err = vfs.WriteFile(c.fsys(), fp, []byte(text), 0o644); err != nil {
+		return fmt.Errorf("save-log: %w", err)
+	}
+	_, err = c.ConsoleBuf.WriteString("saved: " + c.pathText(fp))
+	return err
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+const (
+	rcFile         = ".minderrc"
+	maxSourceDepth = 16
+)
+
+// source는 Call을 다시 호출하므로 commands 초기화 순환을 피해 init에서 등록
+func init() {
+	commands[cmdSource.Name] = cmdSource
+}
+
+var cmdSource = Cmd{
+	Name: "source",
+	Args: []string{"<file>"},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		if len(args) == 0 {
+			return usageError("source: missing argument")
+		}
+		return handleSource(ctx, c, args[0])
+	},
+}
+
+// RunScript r의 minder 명령을 한 줄씩 Call로 실행한다.
+// 빈 줄과 '#' 주석은 건너뛰고, 첫 오류에서 "name:line: err"로 멈춘다.
+func RunScript(ctx context.Context, c *Context, r io.Reader, name string) error {
+	if c.sourceDepth >= maxSourceDepth {
+		return fmt.Errorf("source: %s: too many nested scripts", name)
+	}
+	c.sourceDepth++
+	defer func() { c.sourceDepth-- }()
+
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	wrote := false
+	for sc.Scan() {
+		lineNo++
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, lineNo, err)
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// 명령별 출력 사이 줄바꿈
+		if wrote {
+			c.ConsoleBuf.WriteByte('\n')
+		}
+		before := c.ConsoleBuf.Len()
+		if err := Call(ctx, c, line); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, lineNo, err)
+		}
+		wrote = c.ConsoleBuf.Len() > before
+	}
+	return sc.Err()
+}
+
+// RunRc 시작 시 ~/.minderrc 실행. 파일이 없으면 ran == false
+func RunRc(ctx context.Context, c *Context) (bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, nil
+	}
+
+	fp := filepath.Join(home, rcFile)
+	f, err := os.Open(fp)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	return true, RunScript(ctx, c, f, fp)
+}
+
+func handleSource(ctx context.Context, c *Context, file string) error {
+	fp, err := resolvePath(c, file)
+	if err != nil {
+		return err
+	}
+
+	f, err := c.fsys().Open(fp)
+	if err != nil {
+		return err
+	}
+	defer func(f vfs.File) {
+		fErr := f.Close()
+		if fErr != nil {
+			c.Logger.Error("failed close file", "file", fp, "err", fErr)
+		}
+	}(f)
+
+	return RunScript(ctx, c, f, file)
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// 종료 코드 ($?). 셸 관례를 따른다.
+const (
+	StatusOK         = 0
+	StatusFailure    = 1   // 일반 실패
+	StatusUsage      = 2   // 잘못된 인자/옵션
+	StatusTimeout    = 124 // timeout 명령의 시간 초과
+	StatusPermission = 126 // 권한 없음
+	StatusNotFound   = 127 // 없는 명령
+	StatusCanceled   = 130 // Ctrl+C, 창 닫기
+)
+
+// ExitError 종료 코드가 정해진 명령 오류
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// usageError 인자나 옵션이 잘못됐을 때 (StatusUsage)
+func usageError(format string, a ...any) error {
+	return &ExitError{Code: StatusUsage, Err: fmt.Errorf(format, a...)}
+}
+
+// ExitCode err를 종료 코드로. ExitError가 아니면 오류 종류로 판단한다.
+func ExitCode(err error) int {
+	var ee *ExitError
+	switch {
+	case err == nil, errors.Is(err, ErrExit):
+		return StatusOK
+	case errors.As(err, &ee):
+		return ee.Code
+	case errors.Is(err, context.Canceled):
+		return StatusCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return StatusTimeout
+	case errors.Is(err, fs.ErrPermission):
+		return StatusPermission
+	}
+	return StatusFailure
+}
+
+// StatusText 콘솔의 명령 꼬리말: "✓ 0 · 12ms", 실패면 빨간 "✗ 127 · 3ms"
+func StatusText(code int, elapsed time.Duration) string {
+	s := fmt.Sprintf("%d · %s", code, formatElapsed(elapsed))
+	if code == StatusOK {
+		return paint("✓ "+s, sgrGray)
+	}
+	return paint("✗ "+s, sgrRed)
+}
+
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(10 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
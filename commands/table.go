@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ColumnKind 열 값의 타입. 표시, 정렬, TSV/JSON 변환 방식이 정해진다.
+//   - ColText: 아무 값 (fmt %v)
+//   - ColPath: 절대 경로 string. 터미널에서 누르면 열린다
+//   - ColSize: 바이트 수 int64
+//   - ColTime: time.Time
+type ColumnKind int
+
+const (
+	ColText ColumnKind = iota
+	ColPath
+	ColSize
+	ColTime
+)
+
+const tableTimeLayout = "2006-01-02 15:04"
+
+type Column struct {
+	Name string // JSON 키, 헤더는 대문자로 표시
+	Kind ColumnKind
+}
+
+// Table 명령이 돌려주는 구조화된 결과
+type Table struct {
+	Columns []Column
+	Rows    [][]any
+	Dirs    []bool // 행마다 경로 열이 디렉터리인지 (AppendDir). 터미널이 굵게 표시한다
+	Dir     string // 경로 열을 이 디렉터리 기준 상대 경로로 표시 (비어 있으면 절대 경로)
+}
+
+func NewTable(cols ...Column) *Table {
+	return &Table{Columns: cols}
+}
+
+func (t *Table) Append(row ...any) {
+	t.Rows = append(t.Rows, row)
+	t.Dirs = append(t.Dirs, false)
+}
+
+// AppendDir 경로 열이 디렉터리인 행. 표를 만드는 쪽이 이미 아는 것을 적어 둔다 (그릴 때 Stat하지 않게)
+func (t *Table) AppendDir(row ...any) {
+	t.Rows = append(t.Rows, row)
+	t.Dirs = append(t.Dirs, true)
+}
+
+// IsDir row의 경로 열이 디렉터리인지
+func (t *Table) IsDir(row int) bool {
+	return row < len(t.Dirs) && t.Dirs[row]
+}
+
+func (t *Table) value(row, col int) any {
+	if row >= len(t.Rows) || col >= len(t.Rows[row]) {
+		return nil
+	}
+	return t.Rows[row][col]
+}
+
+// Path 경로 열이면 절대 경로
+func (t *Table) Path(row, col int) (string, bool) {
+	if col >= len(t.Columns) || t.Columns[col].Kind != ColPath {
+		return "", false
+	}
+	p, ok := t.value(row, col).(string)
+	return p, ok && p != ""
+}
+
+// Cell 화면 표시용 문자열
+func (t *Table) Cell(row, col int) string {
+	v := t.value(row, col)
+	if v == nil {
+		return ""
+	}
+	switch t.Columns[col].Kind {
+	case ColPath:
+		p := fmt.Sprint(v)
+		if t.Dir != "" {
+			if rel, err := filepath.Rel(t.Dir, p); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+		}
+		return tildePath(p)
+	case ColSize:
+		if n, ok := v.(int64); ok {
+			return humanSize(n)
+		}
+	case ColTime:
+		if tm, ok := v.(time.Time); ok {
+			return tm.Local().Format(tableTimeLayout)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// raw TSV/JSON용 값: 크기는 바이트, 시간은 RFC3339, 경로는 절대 경로
+func (t *Table) raw(row, col int) any {
+	v := t.value(row, col)
+	if tm, ok := v.(time.Time); ok {
+		return tm.Format(time.RFC3339)
+	}
+	return v
+}
+
+// Less col 기준 row i < row j
+func (t *Table) Less(col, i, j int) bool {
+	a, b := t.value(i, col), t.value(j, col)
+	switch t.Columns[col].Kind {
+	case ColSize:
+		x, _ := a.(int64)
+		y, _ := b.(int64)
+		return x < y
+	case ColTime:
+		x, _ := a.(time.Time)
+		y, _ := b.(time.Time)
+		return x.Before(y)
+	}
+	return strings.ToLower(fmt.Sprint(a)) < strings.ToLower(fmt.Sprint(b))
+}
+
+func (t *Table) WriteTSV(w io.Writer) error {
+	clean := strings.NewReplacer("\t", " ", "\n", " ")
+
+	names := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		names[i] = col.Name
+	}
+	if _, err := io.WriteString(w, strings.Join(names, "\t")+"\n"); err != nil {
+		return err
+	}
+
+	for r := range t.Rows {
+		cells := make([]string, len(t.Columns))
+		for c := range t.Columns {
+			if v := t.raw(r, c); v != nil {
+				cells[c] = clean.Replace(fmt.Sprint(v))
+			}
+		}
+		if _, err := io.WriteString(w, strings.Join(cells, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON 행마다 열 순서대로 키를 둔 객체 배열
+func (t *Table) WriteJSON(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for r := range t.Rows {
+		if r > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n  {")
+		for c, col := range t.Columns {
+			if c > 0 {
+				sb.WriteString(", ")
+			}
+			k, err := json.Marshal(col.Name)
+			if err != nil {
+				return err
+			}
+			v, err := json.Marshal(t.raw(r, c))
+			if err != nil {
+				return err
+			}
+			sb.Write(k)
+			sb.WriteString(": ")
+			sb.Write(v)
+		}
+		sb.WriteString("}")
+	}
+	if len(t.Rows) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("]\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
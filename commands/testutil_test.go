@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// newTestContext fsys 위에서 pwd를 현재 디렉터리로 하는 Context. 확인 질문에는 answers를 차례로 답한다
+func newTestContext(fsys vfs.FS, pwd string, answers ...string) (*Context, *ScriptedPrompter) {
+	p := &ScriptedPrompter{Answers: answers}
+	return &Context{
+		Pwd:        NewString(pwd),
+		Env:        NewEnv(nil),
+		Dirs:       &DirStack{},
+		ConsoleBuf: &Buffer{},
+		Prompter:   p,
+		FS:         fsys,
+		Logger:     slog.New(slog.DiscardHandler),
+	}, p
+}
+
+// run 한 줄을 실행한다
+func run(c *Context, line string) error {
+	return Call(context.Background(), c, line)
+}
+
+// writeFiles 경로 → 내용. 부모 디렉터리도 만든다
+func writeFiles(t *testing.T, fsys vfs.FS, files map[string]string) {
+	t.Helper()
+	for p, data := range files {
+		if err := fsys.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := vfs.WriteFile(fsys, p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkFiles 경로 → 기대하는 내용. 내용이 ""이면 없어야 한다
+func checkFiles(t *testing.T, fsys vfs.FS, want map[string]string) {
+	t.Helper()
+	for p, data := range want {
+		got, err := vfs.ReadFile(fsys, p)
+		switch {
+		case data == "" && err == nil:
+			t.Errorf("%s: should not exist", p)
+		case data != "" && err != nil:
+			t.Errorf("%s: %v", p, err)
+		case data != "" && string(got) != data:
+			t.Errorf("%s = %q, want %q", p, got, data)
+		}
+	}
+}
+
+// fsCase cp, mv, rm 표 테스트 한 줄
+type fsCase struct {
+	name    string
+	files   map[string]string // 처음 상태
+	line    string
+	answers []string // 확인 질문에 차례로 할 답
+	wantErr bool
+	want    map[string]string // checkFiles
+}
+
+// runFSCases 각 경우를 새 Mem 위에서 /work를 현재 디렉터리로 실행한다
+func runFSCases(t *testing.T, tests []fsCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := vfs.NewMem()
+			if err := fsys.MkdirAll("/work", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, fsys, tt.files)
+			c, _ := newTestContext(fsys, "/work", tt.answers...)
+			err := run(c, tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s: err = %v, wantErr %v", tt.line, err, tt.wantErr)
+			}
+			checkFiles(t, fsys, tt.want)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timeout은 명령을 다시 실행하므로 commands 초기화 순환을 피해 init에서 등록
+func init() {
+	commands[cmdTimeout.Name] = cmdTimeout
+}
+
+var cmdTimeout = Cmd{
+	Name: "timeout",
+	Args: []string{"<duration>", "<command>", "[<arg>...]"},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		if len(args) < 2 {
+			return usageError("timeout: missing argument")
+		}
+		d, err := parseTimeout(args[0])
+		if err != nil {
+			return err
+		}
+		return handleTimeout(ctx, c, d, args[1:])
+	},
+}
+
+// parseTimeout "30s", "1m30s" 또는 초 단위 숫자
+func parseTimeout(s string) (time.Duration, error) {
+	if n, err := strconv.ParseFloat(s, 64); err == nil && n > 0 {
+		return time.Duration(n * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, usageError("timeout: invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func handleTimeout(ctx context.Context, c *Context, d time.Duration, words []string) error {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	err := execWords(ctx, c, words, c.argLiteral(words))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &ExitError{Code: StatusTimeout, Err: fmt.Errorf("timeout: %s: timed out after %s", words[0], d)}
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+)
+
+// windowsBackslash \가 경로 구분자라 따옴표, 공백, $ 앞에서만 이스케이프로 본다 (C:\Users\x)
+var windowsBackslash = runtime.GOOS == "windows"
+
+// literalChars 따옴표나 \로 감싸면 단어를 글롭, 중괄호 확장에서 빼는 글자
+const literalChars = "*?[{!"
+
+// isLiteral literal[i]: i번째 단어를 따옴표나 \로 감싸 글롭, 중괄호 확장하지 않는지. 범위 밖은 false
+func isLiteral(literal []bool, i int) bool {
+	return i < len(literal) && literal[i]
+}
+
+// tokenize 명령줄을 단어로 나눈다.
+//   - 공백으로 구분, '...'는 글자 그대로, "..."는 안에서 $ 확장
+//   - \ 는 다음 글자를 그대로 (작은따옴표 안 제외, Windows는 windowsBackslash)
+//   - $NAME, ${NAME}, $? 확장. 값은 단어를 나누지 않는다(경로에 공백이 있어도 한 단어)
+func tokenize(line string, lookup func(string) (string, bool)) ([]string, error) {
+	words, _, err := tokenizeLiteral(line, lookup)
+	return words, err
+}
+
+// tokenizeLiteral tokenize와 같지만 단어마다 글롭, 중괄호 문자를 따옴표나 \로 감쌌는지도 돌려준다
+func tokenizeLiteral(line string, lookup func(string) (string, bool)) ([]string, []bool, error) {
+	var words []string
+	var literal []bool
+	var cur strings.Builder
+	inWord := false // 빈 따옴표("")도 단어로 남기기 위함
+	quoted := false // 감싼 글자 중에 literalChars가 있다
+
+	flush := func() {
+		if inWord {
+			words = append(words, cur.String())
+			literal = append(literal, quoted)
+		}
+		cur.Reset()
+		inWord = false
+		quoted = false
+	}
+	// quote 따옴표나 \로 감싼 글자들
+	quote := func(s string) {
+		cur.WriteString(s)
+		quoted = quoted || strings.ContainsAny(s, literalChars)
+	}
+
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			flush()
+		case ch == '\\':
+			if windowsBackslash && (i+1 >= len(line) || strings.IndexByte("'\" \t$", line[i+1]) < 0) {
+				cur.WriteByte(ch)
+				inWord = true
+			} else if i+1 < len(line) {
+				i++
+				quote(line[i : i+1])
+				inWord = true
+			}
+		case ch == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, nil, errors.New("unterminated quote")
+			}
+			quote(line[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case ch == '"':
+			escapes := `"\$`
+			if windowsBackslash {
+				escapes = `"$`
+			}
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(line) && line[j] != '"'; j++ {
+				switch {
+				case line[j] == '\\' && j+1 < len(line) && strings.IndexByte(escapes, line[j+1]) >= 0:
+					j++
+					sb.WriteByte(line[j])
+				case line[j] == '$':
+					j = expandVar(line, j, lookup, &sb) - 1
+				default:
+					sb.WriteByte(line[j])
+				}
+			}
+			if j >= len(line) {
+				return nil, nil, errors.New("unterminated quote")
+			}
+			quote(sb.String())
+			i = j
+			inWord = true
+		case ch == '$':
+			start := cur.Len()
+			i = expandVar(line, i, lookup, &cur) - 1
+			// 따옴표 없는 빈 변수만으로는 단어를 만들지 않는다
+			if cur.Len() > start {
+				inWord = true
+			}
+		default:
+			cur.WriteByte(ch)
+			inWord = true
+		}
+	}
+	flush()
+	return words, literal, nil
+}
+
+// expandVar line[i] == '$' 위치의 변수를 확장해 sb에 쓰고 다음 읽을 위치를 돌려준다
+func expandVar(line string, i int, lookup func(string) (string, bool), sb *strings.Builder) int {
+	j := i + 1
+	if j >= len(line) {
+		sb.WriteByte('$')
+		return j
+	}
+
+	var name string
+	switch {
+	case line[j] == '?':
+		name, j = "?", j+1
+	case line[j] == '{':
+		end := strings.IndexByte(line[j:], '}')
+		if end < 0 {
+			sb.WriteByte('$')
+			return i + 1
+		}
+		name, j = line[j+1:j+end], j+end+1
+	default:
+		k := j
+		for k < len(line) && isNameByte(line[k], k == j) {
+			k++
+		}
+		if k == j {
+			// "$" 뒤에 이름이 없으면 글자 그대로
+			sb.WriteByte('$')
+			return j
+		}
+		name, j = line[j:k], k
+	}
+
+	if v, ok := lookup(name); ok {
+		sb.WriteString(v)
+	}
+	return j
+}
+
+func isNameByte(b byte, first bool) bool {
+	switch {
+	case b == '_', 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z':
+		return true
+	case '0' <= b && b <= '9':
+		return !first
+	}
+	return false
+}
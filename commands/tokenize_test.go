@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	vars := map[string]string{"HOME": "/home/me", "PAT": "*.go"}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+	tests := []struct {
+		line        string
+		windows     bool
+		want        []string
+		wantLiteral []bool
+	}{
+		{line: `ls -l  a b`, want: []string{"ls", "-l", "a", "b"}},
+		{line: `cd "$HOME/my dir"`, want: []string{"cd", "/home/me/my dir"}},
+		{line: `echo '$HOME' ""`, want: []string{"echo", "$HOME", ""}},
+		{line: `rm "*.txt" *.log`, want: []string{"rm", "*.txt", "*.log"}, wantLiteral: []bool{false, true, false}},
+		// 같은 글자라도 감싼 자리만
+		{line: `rm '*.log' *.log`, want: []string{"rm", "*.log", "*.log"}, wantLiteral: []bool{false, true, false}},
+		{line: `mkdir '{a,b}' \*x`, want: []string{"mkdir", "{a,b}", "*x"}, wantLiteral: []bool{false, true, true}},
+		{line: `ls "$PAT" $PAT`, want: []string{"ls", "*.go", "*.go"}, wantLiteral: []bool{false, true, false}},
+		// 글롭 문자 없는 부분만 감쌌으면 펼친다
+		{line: `ls "my dir"/*.txt`, want: []string{"ls", "my dir/*.txt"}},
+		{line: `cd a\ b "x\"y"`, want: []string{"cd", "a b", `x"y`}},
+		{line: `cd C:\Users\x`, want: []string{"cd", "C:Usersx"}},
+		{line: `cd C:\Users\x`, windows: true, want: []string{"cd", `C:\Users\x`}},
+		{line: `cd "C:\Program Files\x"`, windows: true, want: []string{"cd", `C:\Program Files\x`}},
+		{line: `cd C:\my\ dir \$HOME \"q\"`, windows: true, want: []string{"cd", `C:\my dir`, "$HOME", `"q"`}},
+		{line: `cd dir\`, windows: true, want: []string{"cd", `dir\`}},
+	}
+	for _, tt := range tests {
+		prev := windowsBackslash
+		windowsBackslash = tt.windows
+		got, literal, err := tokenizeLiteral(tt.line, lookup)
+		windowsBackslash = prev
+		if err != nil {
+			t.Errorf("%s: %v", tt.line, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %q, want %q", tt.line, got, tt.want)
+		}
+		wantLiteral := tt.wantLiteral
+		if wantLiteral == nil {
+			wantLiteral = make([]bool, len(tt.want))
+		}
+		if !slices.Equal(literal, wantLiteral) {
+			t.Errorf("%s: literal = %v, want %v", tt.line, literal, wantLiteral)
+		}
+	}
+
+	for _, line := range []string{`echo "abc`, `echo 'abc`} {
+		if _, err := tokenize(line, lookup); err == nil {
+			t.Errorf("%s: want unterminated quote", line)
+		}
+	}
+}
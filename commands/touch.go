@@ -1,35 +1,139 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/meteormin/minder/vfs"
 )
 
 var cmdTouch = Cmd{
 	Name: "touch",
-	Args: []string{"<dst>"},
-	Exec: func(c *Context, args []string) error {
-		if len(args) == 0 {
-			return errors.New("touch: missing argument")
+	Args: []string{"[-acm]", "[-d date | -r ref]", "<file>..."},
+	Exec: func(ctx context.Context, c *Context, args []string) error {
+		opts, files, err := getopt("touch", args, "acmd:r:")
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return usageError("touch: missing file operand")
+		}
+
+		t := time.Now()
+		switch {
+		case opts.has('d') && opts.has('r'):
+			return errors.New("touch: cannot specify times from more than one source")
+		case opts.has('d'):
+			if t, err = parseTouchDate(opts.get('d')); err != nil {
+				return err
+			}
+		case opts.has('r'):
+			ref, err := resolvePath(c, opts.get('r'))
+			if err != nil {
+				return err
+			}
+			fi, err := c.fsys().Stat(ref)
+			if err != nil {
+				return fmt.Errorf("touch: failed to get attributes of %q: %w", opts.get('r'), err)
+			}
+			t = fi.ModTime()
+		}
+
+		// -a/-m 둘 다 없으면 둘 다 갱신. zero time은 Chtimes에서 "변경 안 함"
+		atime, mtime := t, t
+		if opts.has('a') && !opts.has('m') {
+			mtime = time.Time{}
+		}
+		if opts.has('m') && !opts.has('a') {
+			atime = time.Time{}
 		}
-		return handleTouch(c, args[0])
+		files, literal, err := expandBraceArgs(files, c.argLiteral(files))
+		if err != nil {
+			return fmt.Errorf("touch: %w", err)
+		}
+		return handleTouch(ctx, c, files, literal, !opts.has('c'), atime, mtime)
 	},
 }
 
-func handleTouch(c *Context, dst string) error {
-	fp, err := pathToAbs(c, dst)
-	if err != nil {
-		return err
+var touchDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func parseTouchDate(s string) (time.Time, error) {
+	for _, layout := range touchDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, usageError("touch: invalid date format %q", s)
+}
+
+// handleTouch literal[i]이면 dsts[i]를 글롭 확장하지 않는다
+func handleTouch(ctx context.Context, c *Context, dsts []string, literal []bool, create bool, atime, mtime time.Time) error {
+	var done []string
+	var errs []error
+	for i, dst := range dsts {
+		fp, err := resolvePath(c, dst)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// 글롭은 매치된 파일들을 갱신하고, 매치가 없으면 글자 그대로 생성
+		targets := []string{fp}
+		if hasGlob(fp) && !isLiteral(literal, i) {
+			if matches, err := expandPattern(ctx, c.fsys(), fp); err == nil {
+				targets = matches
+			}
+		}
+
+		for _, target := range targets {
+			touched, err := touchFile(c.fsys(), target, create, atime, mtime)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if touched {
+				done = append(done, "touch: "+c.pathText(target))
+			}
+		}
 	}
 
-	_, err = os.Create(fp)
-	if err != nil {
-		return err
+	if len(done) > 0 {
+		c.refreshSideBar()
+		if _, err := c.ConsoleBuf.WriteString(strings.Join(done, "\n")); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	c.RefreshSideBar()
+// touchFile 없으면 빈 파일 생성(내용은 절대 자르지 않음), 있으면 시간만 갱신
+func touchFile(fsys vfs.FS, fp string, create bool, atime, mtime time.Time) (bool, error) {
+	if !vfs.Exists(fsys, fp) {
+		if !create {
+			// -c: 없는 파일은 조용히 무시
+			return false, nil
+		}
+		f, err := fsys.OpenFile(fp, os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return false, err
+		}
+		if err = f.Close(); err != nil {
+			return false, err
+		}
+	}
 
-	_, err = fmt.Fprintf(c.ConsoleBuf, "touch: %s", fp)
-	return err
+	if err := fsys.Chtimes(fp, atime, mtime); err != nil {
+		return false, err
+	}
+	return true, nil
 }
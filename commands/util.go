@@ -1,51 +1,114 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/user"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
+
+	"fyne.io/fyne/v2"
+	"github.com/meteormin/minder/vfs"
 )
 
-func pathToAbs(c *Context, dst string) (string, error) {
-	var fp string
-	if filepath.IsAbs(dst) {
-		fp = filepath.Dir(dst)
-		if _, err := os.Stat(fp); err != nil {
+// resolvePath 명령 인자를 절대 경로로 변환한다.
+// "~", "~user"를 확장하고 상대 경로는 Pwd 기준으로 붙인 뒤 ".."까지 정리한다.
+// 대상이 아직 없어도 된다(mkdir, touch, cp 대상 등). 존재 여부는 호출하는 쪽에서 확인한다.
+func resolvePath(c *Context, p string) (string, error) {
+	if p == "" {
+		return "", errors.New("empty path")
+	}
+
+	fp, err := expandTilde(p)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(fp) {
+		base, err := baseDir(c)
+		if err != nil {
+			return "", err
+		}
+		fp = filepath.Join(base, fp)
+	}
+	return filepath.Clean(fp), nil
+}
+
+// resolveOperand resolvePath와 같지만 "aDir/." 표기는 보존한다. (cp/mv/rm 소스용)
+func resolveOperand(c *Context, p string) (string, error) {
+	fp, err := resolvePath(c, p)
+	if err != nil {
+		return "", err
+	}
+	if p != "." && filepath.Base(p) == "." {
+		fp = strings.TrimSuffix(fp, string(filepath.Separator)) + string(filepath.Separator) + "."
+	}
+	return fp, nil
+}
+
+// expandTilde "~", "~/x", "~user", "~user/x" 확장
+func expandTilde(p string) (string, error) {
+	if !strings.HasPrefix(p, "~") {
+		return p, nil
+	}
+
+	name, rest := p[1:], ""
+	if i := strings.IndexAny(name, `/`+string(filepath.Separator)); i >= 0 {
+		name, rest = name[:i], name[i+1:]
+	}
+
+	var home string
+	if name == "" {
+		h, err := os.UserHomeDir()
+		if err != nil {
 			return "", err
 		}
-		return fp, nil
+		home = h
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", fmt.Errorf("unknown user %q: %w", name, err)
+		}
+		home = u.HomeDir
 	}
+	return filepath.Join(home, rest), nil
+}
 
+// baseDir 상대 경로의 기준 디렉터리. Pwd가 파일(또는 파일 링크)을 가리키면 그 부모를 쓴다.
+func baseDir(c *Context) (string, error) {
 	currentDir, _ := c.Pwd.Get()
-	s, err := os.Lstat(currentDir)
+	fsys := c.fsys()
+	s, err := fsys.Lstat(currentDir)
 	if err != nil {
 		return "", err
 	}
 
 	isDir := s.IsDir()
 	if !isDir && s.Mode()&os.ModeSymlink != 0 {
-		if ti, err := os.Stat(currentDir); err == nil && ti.IsDir() {
-			isDir = true
-		}
+		isDir = vfs.IsDir(fsys, currentDir)
 	}
 
-	if isDir {
-		fp = filepath.Join(currentDir, dst)
-	} else {
-		fp = filepath.Join(filepath.Dir(currentDir), dst)
+	if isDir || vfs.IsArchiveDir(fsys, currentDir) {
+		return currentDir, nil
 	}
+	return filepath.Dir(currentDir), nil
+}
 
-	if _, err = os.Stat(fp); err != nil {
+// configPath minder 설정 디렉터리(예: ~/.config/minder) 아래 파일 경로
+func configPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
 		return "", err
 	}
-	return fp, nil
+	return filepath.Join(dir, "minder", name), nil
 }
 
-func exists(p string) bool { _, err := os.Lstat(p); return err == nil }
+func (c *Context) exists(p string) bool { return vfs.Exists(c.fsys(), p) }
 
 func isSubpath(child, parent string) bool {
 	rel, err := filepath.Rel(parent, child)
@@ -63,45 +126,42 @@ func isCrossDevice(err error) bool {
 	return errors.Is(err, syscall.EXDEV)
 }
 
-func expandPattern(p string) ([]string, error) {
-	if !hasGlob(p) {
-		return []string{p}, nil
-	}
-	matches, err := filepath.Glob(p)
-	if err != nil {
-		return nil, err
-	}
-	basePat := filepath.Base(p)
-	if strings.HasPrefix(basePat, ".") {
-		// ".*" 류: "." ".." 제거
-		out := matches[:0]
-		for _, m := range matches {
-			b := filepath.Base(m)
-			if b == "." || b == ".." {
-				continue
-			}
-			out = append(out, m)
+// patternMiss 펼치지 못한 소스 패턴
+type patternMiss struct {
+	pattern string
+	err     error
+}
+
+// expandSources 소스 패턴들을 펼친다. "aDir/."과 따옴표로 감싼 것(literal[i])은 그대로 두고,
+// 매치되지 않는 패턴은 따로 모아 나머지 패턴 처리를 계속한다.
+func expandSources(ctx context.Context, fsys vfs.ReadFS, patterns []string, literal []bool) ([]string, []patternMiss) {
+	var srcs []string
+	var misses []patternMiss
+	for i, p := range patterns {
+		if _, ok := asDotContents(p); ok || isLiteral(literal, i) {
+			srcs = append(srcs, p)
+			continue
 		}
-		matches = out
-	} else {
-		// "*" 류: dotfile 제외
-		out := matches[:0]
-		for _, m := range matches {
-			if strings.HasPrefix(filepath.Base(m), ".") {
-				continue
-			}
-			out = append(out, m)
+		matches, err := expandPattern(ctx, fsys, p)
+		if err != nil {
+			misses = append(misses, patternMiss{pattern: p, err: err})
+			continue
 		}
-		matches = out
+		srcs = append(srcs, matches...)
 	}
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no matches for %q", p)
-	}
-	return matches, nil
+	return srcs, misses
 }
 
-// hasGlob: 글롭 문자가 있는지
-func hasGlob(p string) bool { return strings.ContainsAny(p, "*?[") }
+// checkMultiTarget 소스가 둘 이상이면 목적지는 디렉터리여야 한다
+func checkMultiTarget(fsys vfs.ReadFS, n int, dst string) error {
+	if n <= 1 {
+		return nil
+	}
+	if !vfs.IsDir(fsys, dst) {
+		return fmt.Errorf("target %q is not a directory for multiple sources", dst)
+	}
+	return nil
+}
 
 // asDotContents: "aDir/." → ("aDir", true)
 func asDotContents(p string) (string, bool) {
@@ -112,7 +172,42 @@ func asDotContents(p string) (string, bool) {
 	return "", false
 }
 
+// isNotEmpty 디렉터리가 비어있지 않아 삭제 실패
+func isNotEmpty(err error) bool {
+	return errors.Is(err, syscall.ENOTEMPTY) || errors.Is(err, syscall.EEXIST)
+}
+
 func shouldFallbackRename(err error) bool {
 	// Windows 드라이브 간 이동 등 다양한 에러 → 폴백 권장
 	return runtime.GOOS == "windows"
 }
+
+// isCanceled 명령이 취소(Ctrl+C, 창 닫기)되었거나 timeout이 지났는지
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// ctxReader 큰 파일 복사 도중에도 취소되도록 Read마다 ctx 확인
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// awaitChoice 다이얼로그 응답 대기. 기다리는 중에 취소되면 다이얼로그를 닫는다.
+// hide는 UI 스레드에서 호출된다.
+func awaitChoice(ctx context.Context, ch <-chan string, hide func()) (string, error) {
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		fyne.Do(hide)
+		return "", ctx.Err()
+	}
+}
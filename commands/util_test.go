@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+func TestExpandTilde(t *testing.T) {
+	home := filepath.FromSlash("/home/tester")
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	me, err := user.Current()
+	if err != nil {
+		t.Skip(err)
+	}
+	// Windows의 사용자 이름은 "DOMAIN\name"
+	name := filepath.Base(me.Username)
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "~", want: home},
+		{in: "~/", want: home},
+		{in: "~/x", want: filepath.Join(home, "x")},
+		{in: "~/x/y/", want: filepath.Join(home, "x", "y")},
+		{in: "~" + name, want: me.HomeDir},
+		{in: "~" + name + "/docs", want: filepath.Join(me.HomeDir, "docs")},
+		{in: "~no-such-user-minder", wantErr: true},
+		{in: "x/~", want: "x/~"},
+		{in: "/abs/~x", want: "/abs/~x"},
+	}
+	for _, tt := range tests {
+		got, err := expandTilde(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expandTilde(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("expandTilde(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("paths below are unix style")
+	}
+	t.Setenv("HOME", "/home/tester")
+
+	fsys := vfs.NewMem()
+	writeFiles(t, fsys, map[string]string{"/work/sub/file.txt": "x"})
+
+	tests := []struct {
+		name    string
+		pwd     string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "relative", pwd: "/work", in: "sub", want: "/work/sub"},
+		{name: "relative nested", pwd: "/work", in: "sub/file.txt", want: "/work/sub/file.txt"},
+		{name: "dot", pwd: "/work", in: ".", want: "/work"},
+		{name: "parent", pwd: "/work/sub", in: "..", want: "/work"},
+		{name: "above root", pwd: "/work", in: "../../../..", want: "/"},
+		{name: "absolute", pwd: "/work", in: "/etc/hosts", want: "/etc/hosts"},
+		{name: "absolute unclean", pwd: "/work", in: "/a/./b/../c", want: "/a/c"},
+		{name: "tilde", pwd: "/work", in: "~/x", want: "/home/tester/x"},
+		{name: "missing target", pwd: "/work", in: "nope/new.txt", want: "/work/nope/new.txt"},
+		{name: "trailing separator", pwd: "/work", in: "sub/", want: "/work/sub"},
+		{name: "double separator", pwd: "/work", in: "sub//file.txt", want: "/work/sub/file.txt"},
+		{name: "pwd is file", pwd: "/work/sub/file.txt", in: "other.txt", want: "/work/sub/other.txt"},
+		{name: "pwd is file parent", pwd: "/work/sub/file.txt", in: "..", want: "/work"},
+		{name: "pwd is file absolute", pwd: "/work/sub/file.txt", in: "/x", want: "/x"},
+		{name: "pwd missing", pwd: "/gone", in: "x", wantErr: true},
+		{name: "pwd missing absolute", pwd: "/gone", in: "/x", want: "/x"},
+		{name: "empty", pwd: "/work", in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(fsys, tt.pwd)
+			got, err := resolvePath(c, tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePath(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("resolvePath(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOperand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("paths below are unix style")
+	}
+	fsys := vfs.NewMem()
+	writeFiles(t, fsys, map[string]string{"/work/d/a.txt": "a"})
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "d", want: "/work/d"},
+		{in: "d/", want: "/work/d"},
+		{in: "d/.", want: "/work/d/."},
+		{in: "./d/.", want: "/work/d/."},
+		{in: ".", want: "/work"},
+		{in: "/work/d/.", want: "/work/d/."},
+	}
+	for _, tt := range tests {
+		c, _ := newTestContext(fsys, "/work")
+		got, err := resolveOperand(c, tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveOperand(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
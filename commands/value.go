@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2/data/binding"
+)
+
+// stringValue fyne 앱 없이 쓰는 binding.String.
+// binding.NewString은 값 변경을 fyne.Do로 알리므로 창 없이 실행할 때(--script 등)는 이것을 쓴다.
+// 리스너는 Set을 호출한 고루틴에서 바로 호출된다.
+type stringValue struct {
+	mu        sync.RWMutex
+	v         string
+	listeners []binding.DataListener
+}
+
+func NewString(v string) binding.String {
+	return &stringValue{v: v}
+}
+
+func (s *stringValue) Get() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.v, nil
+}
+
+func (s *stringValue) Set(v string) error {
+	s.mu.Lock()
+	if s.v == v {
+		s.mu.Unlock()
+		return nil
+	}
+	s.v = v
+	listeners := append([]binding.DataListener(nil), s.listeners...)
+	s.mu.Unlock()
+
+	for _, l := range listeners {
+		l.DataChanged()
+	}
+	return nil
+}
+
+func (s *stringValue) AddListener(l binding.DataListener) {
+	s.mu.Lock()
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+	l.DataChanged()
+}
+
+func (s *stringValue) RemoveListener(l binding.DataListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, cur := range s.listeners {
+		if cur == l {
+			s.listeners = append(s.listeners[:i], s.listeners[i+1:]...)
+			return
+		}
+	}
+}
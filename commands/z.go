@@ -0,0 +1,330 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+const (
+	frecencyFile = "frecency"
+	// 전체 rank 합이 이 값을 넘으면 모든 항목을 줄이고 1 미만은 버린다 (z 방식)
+	frecencyMaxRank = 9000
+	// 2등 점수가 1등의 이 비율 이상이면 모호하다고 보고 고르게 한다
+	ambiguousRatio = 0.8
+)
+
+var (
+	cmdZ = Cmd{
+		Name: "z",
+		Args: []string{"[-l]", "<fragment>..."},
+		Exec: func(ctx context.Context, c *Context, args []string) error {
+			opts, rest, err := getopt("z", args, "l")
+			if err != nil {
+				return err
+			}
+			if opts.has('l') {
+				return handleZList(c, rest)
+			}
+			if len(rest) == 0 {
+				return usageError("z: missing fragment")
+			}
+			return handleZ(ctx, c, rest)
+		},
+	}
+
+	frecency = &frecencyStore{}
+)
+
+// dirEntry 방문 기록 한 줄: "rank<TAB>unix time<TAB>"path"" (경로는 Go 따옴표 문자열)
+type dirEntry struct {
+	path string
+	rank float64
+	time int64
+}
+
+// score 최근 방문일수록 가중치를 높인다
+func (e dirEntry) score(now int64) float64 {
+	dt := now - e.time
+	switch {
+	case dt < 3600:
+		return e.rank * 4
+	case dt < 86400:
+		return e.rank * 2
+	case dt < 604800:
+		return e.rank / 2
+	default:
+		return e.rank / 4
+	}
+}
+
+type frecencyStore struct {
+	mu sync.Mutex
+}
+
+// load 다른 minder 창도 같은 파일을 쓰므로 매번 읽는다
+func (s *frecencyStore) load() ([]dirEntry, error) {
+	fp, err := configPath(frecencyFile)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(fp)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	var ents []dirEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if e, ok := parseDirEntry(sc.Text()); ok {
+			ents = append(ents, e)
+		}
+	}
+	return ents, sc.Err()
+}
+
+// parseDirEntry 예전 "path|rank|unix time" 줄도 읽는다 (경로 속 |는 앞쪽에 남는다)
+func parseDirEntry(line string) (dirEntry, bool) {
+	var path, rank, t string
+	if parts := strings.SplitN(line, "\t", 3); len(parts) == 3 {
+		p, err := strconv.Unquote(parts[2])
+		if err != nil {
+			return dirEntry{}, false
+		}
+		path, rank, t = p, parts[0], parts[1]
+	} else {
+		i := strings.LastIndexByte(line, '|')
+		j := strings.LastIndexByte(line[:max(i, 0)], '|')
+		if j < 0 {
+			return dirEntry{}, false
+		}
+		path, rank, t = line[:j], line[j+1:i], line[i+1:]
+	}
+	if path == "" {
+		return dirEntry{}, false
+	}
+	r, err := strconv.ParseFloat(rank, 64)
+	if err != nil {
+		return dirEntry{}, false
+	}
+	sec, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return dirEntry{}, false
+	}
+	return dirEntry{path: path, rank: r, time: sec}, true
+}
+
+func (s *frecencyStore) save(ents []dirEntry) error {
+	fp, err := configPath(frecencyFile)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, e := range ents {
+		sb.WriteString(fmt.Sprintf("%s\t%d\t%q\n", strconv.FormatFloat(e.rank, 'f', -1, 64), e.time, e.path))
+	}
+	return os.WriteFile(fp, []byte(sb.String()), 0o644)
+}
+
+func (s *frecencyStore) add(dir string, now int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ents, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	total := 0.0
+	for i := range ents {
+		if ents[i].path == dir {
+			ents[i].rank++
+			ents[i].time = now
+			found = true
+		}
+		total += ents[i].rank
+	}
+	if !found {
+		ents = append(ents, dirEntry{path: dir, rank: 1, time: now})
+		total++
+	}
+
+	// 에이징
+	if total > frecencyMaxRank {
+		kept := ents[:0]
+		for _, e := range ents {
+			e.rank *= 0.99
+			if e.rank >= 1 {
+				kept = append(kept, e)
+			}
+		}
+		ents = kept
+	}
+	return s.save(ents)
+}
+
+// match fragment들이 순서대로 경로에 들어있는(대소문자 무시) 디렉터리를 점수순으로.
+// 더 이상 없는 디렉터리는 기록에서 지운다 (isGone).
+func (s *frecencyStore) match(fsys vfs.ReadFS, fragments []string, now int64) ([]dirEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ents, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		kept    = ents[:0]
+		matches []dirEntry
+		pruned  bool
+	)
+	for _, e := range ents {
+		if isGone(fsys, e.path) {
+			pruned = true
+			continue
+		}
+		kept = append(kept, e)
+		if matchFragments(e.path, fragments) {
+			matches = append(matches, e)
+		}
+	}
+	if pruned {
+		if err = s.save(kept); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score(now) > matches[j].score(now)
+	})
+	return matches, nil
+}
+
+// isGone 로컬 디스크에서 사라진 디렉터리인지. 마운트 지점(/@name) 아래와 원격, 아카이브 안은
+// 연결되지 않았거나 느릴 수 있어 지우지 않는다
+func isGone(fsys vfs.ReadFS, p string) bool {
+	if strings.HasPrefix(p, vfs.MountPoint("")) {
+		return false
+	}
+	lp, ok := vfs.LocalPath(fsys, p)
+	if !ok {
+		return false
+	}
+	fi, err := os.Stat(lp)
+	return err != nil || !fi.IsDir()
+}
+
+func matchFragments(p string, fragments []string) bool {
+	p = strings.ToLower(p)
+	for _, frag := range fragments {
+		// 소문자로 바꾸면 바이트 길이가 달라질 수 있다 (KELVIN SIGN \u212a → "k")
+		frag = strings.ToLower(frag)
+		i := strings.Index(p, frag)
+		if i < 0 {
+			return false
+		}
+		p = p[i+len(frag):]
+	}
+	return true
+}
+
+// RecordDir 디렉터리 방문 기록. cd와 트리 탐색에서 호출한다.
+func RecordDir(dir string) error {
+	return frecency.add(filepath.Clean(dir), time.Now().Unix())
+}
+
+func handleZ(ctx context.Context, c *Context, fragments []string) error {
+	now := time.Now().Unix()
+	matches, err := frecency.match(c.fsys(), fragments, now)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("z: no match for %q", strings.Join(fragments, " "))
+	}
+
+	dst := matches[0].path
+	if len(matches) > 1 && matches[1].score(now) >= matches[0].score(now)*ambiguousRatio {
+		var cands []string
+		for _, m := range matches {
+			if m.score(now) < matches[0].score(now)*ambiguousRatio {
+				break
+			}
+			cands = append(cands, m.path)
+		}
+		dst, err = resolveDirChoice(ctx, c, cands)
+		if errors.Is(err, errNoPrompter) {
+			// 물어볼 곳이 없으면(스크립트) 최고 점수로
+			dst, err = cands[0], nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return handleChangeDirectory(ctx, c, dst, true)
+}
+
+func handleZList(c *Context, fragments []string) error {
+	now := time.Now().Unix()
+	matches, err := frecency.match(c.fsys(), fragments, now)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	// 낮은 점수부터 (가장 유력한 후보가 프롬프트 바로 위)
+	for i := len(matches) - 1; i >= 0; i-- {
+		_, _ = fmt.Fprintf(w, "%.1f\t%s\n", matches[i].score(now), dirText(tildePath(matches[i].path)))
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	_, err = c.ConsoleBuf.WriteString(strings.TrimSuffix(sb.String(), "\n"))
+	return err
+}
+
+// resolveDirChoice 후보 목록에서 하나 고르기
+func resolveDirChoice(ctx context.Context, c *Context, cands []string) (string, error) {
+	p, err := c.prompter()
+	if err != nil {
+		return "", err
+	}
+
+	options := make([]string, len(cands))
+	for i, d := range cands {
+		options[i] = tildePath(d)
+	}
+	choice, err := p.Choose(ctx, "z: choose directory", options)
+	if err != nil {
+		return "", err
+	}
+	if i := slices.Index(options, choice); choice != "" && i >= 0 {
+		return cands[i], nil
+	}
+	return "", errors.New("z: cancelled")
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/meteormin/minder/vfs"
+)
+
+// 경로에 |, 탭, 줄바꿈이 있어도 그대로 읽고, 예전 "path|rank|time" 줄도 읽는다
+func TestFrecencyFormat(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+
+	want := []dirEntry{
+		{path: "/a|b|1", rank: 2.5, time: 100},
+		{path: "/tab\tdir", rank: 1, time: 200},
+		{path: "/new\nline", rank: 3, time: 300},
+	}
+	s := &frecencyStore{}
+	if err := s.save(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("load = %v, want %v", got, want)
+	}
+
+	fp, err := configPath(frecencyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(fp, []byte("/old|4|400\n/x|y|1.5|500\nbroken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []dirEntry{{path: "/old", rank: 4, time: 400}, {path: "/x|y", rank: 1.5, time: 500}}
+	if !slices.Equal(got, want) {
+		t.Errorf("legacy load = %v, want %v", got, want)
+	}
+}
+
+// 사라진 로컬 디렉터리만 기록에서 지우고 마운트 지점 아래는 남긴다
+func TestFrecencyPrune(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+
+	root := t.TempDir()
+	kept := filepath.Join(root, "proj")
+	if err := os.Mkdir(kept, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gone := filepath.Join(root, "gone")
+	remote := filepath.Join(vfs.MountPoint("dav"), "proj")
+
+	s := &frecencyStore{}
+	for _, d := range []string{kept, gone, remote} {
+		if err := s.add(d, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.match(vfs.NewMux(vfs.OS), []string{"proj"}, 100); err != nil {
+		t.Fatal(err)
+	}
+	ents, err := s.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range ents {
+		got = append(got, e.path)
+	}
+	if want := []string{kept, remote}; !slices.Equal(got, want) {
+		t.Errorf("kept %v, want %v", got, want)
+	}
+}
+
+func TestMatchFragments(t *testing.T) {
+	tests := []struct {
+		path      string
+		fragments []string
+		want      bool
+	}{
+		{path: "/home/me/Projects/minder", fragments: []string{"proj", "mind"}, want: true},
+		{path: "/home/me/Projects/minder", fragments: []string{"mind", "proj"}, want: false},
+		{path: "/home/me/work", fragments: []string{"WORK"}, want: true},
+		// KELVIN SIGN(\u212a)은 3바이트, 소문자 k는 1바이트
+		{path: "/home/me/kit", fragments: []string{"\u212a"}, want: true},
+		{path: "/home/me/kit", fragments: []string{"\u212ai", "t"}, want: true},
+		{path: "/home/me/kit", fragments: []string{"\u212a", "x"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := matchFragments(tt.path, tt.fragments); got != tt.want {
+			t.Errorf("matchFragments(%q, %q) = %v, want %v", tt.path, tt.fragments, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package components
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ansiPalette SGR 30-37 / 90-97 (배경은 40-47 / 100-107) 순서
+var ansiPalette = [16]color.Color{
+	color.NRGBA{R: 0x2e, G: 0x34, B: 0x36, A: 0xff}, // black
+	color.NRGBA{R: 0xcc, G: 0x00, B: 0x00, A: 0xff}, // red
+	color.NRGBA{R: 0x4e, G: 0x9a, B: 0x06, A: 0xff}, // green
+	color.NRGBA{R: 0xc4, G: 0xa0, B: 0x00, A: 0xff}, // yellow
+	color.NRGBA{R: 0x34, G: 0x65, B: 0xa4, A: 0xff}, // blue
+	color.NRGBA{R: 0x75, G: 0x50, B: 0x7b, A: 0xff}, // magenta
+	color.NRGBA{R: 0x06, G: 0x98, B: 0x9a, A: 0xff}, // cyan
+	color.NRGBA{R: 0xd3, G: 0xd7, B: 0xcf, A: 0xff}, // white
+	color.NRGBA{R: 0x55, G: 0x57, B: 0x53, A: 0xff}, // bright black
+	color.NRGBA{R: 0xef, G: 0x29, B: 0x29, A: 0xff},
+	color.NRGBA{R: 0x8a, G: 0xe2, B: 0x34, A: 0xff},
+	color.NRGBA{R: 0xfc, G: 0xe9, B: 0x4f, A: 0xff},
+	color.NRGBA{R: 0x72, G: 0x9f, B: 0xcf, A: 0xff},
+	color.NRGBA{R: 0xad, G: 0x7f, B: 0xa8, A: 0xff},
+	color.NRGBA{R: 0x34, G: 0xe2, B: 0xe2, A: 0xff},
+	color.NRGBA{R: 0xee, G: 0xee, B: 0xec, A: 0xff},
+}
+
+// sgrState 현재 적용 중인 색/스타일
+type sgrState struct {
+	fg, bg    color.Color
+	bold      bool
+	underline bool
+}
+
+// style 기본 상태면 nil (테마 색 사용)
+func (s sgrState) style() widget.TextGridStyle {
+	if s == (sgrState{}) {
+		return nil
+	}
+	return &widget.CustomTextGridStyle{
+		TextStyle: fyne.TextStyle{Bold: s.bold, Underline: s.underline},
+		FGColor:   s.fg,
+		BGColor:   s.bg,
+	}
+}
+
+// apply "1;31" 같은 SGR 인자 적용
+func (s *sgrState) apply(params string) {
+	if params == "" {
+		*s = sgrState{}
+		return
+	}
+	ps := strings.Split(params, ";")
+	for i := 0; i < len(ps); i++ {
+		n, err := strconv.Atoi(ps[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 0:
+			*s = sgrState{}
+		case n == 1:
+			s.bold = true
+		case n == 22:
+			s.bold = false
+		case n == 4:
+			s.underline = true
+		case n == 24:
+			s.underline = false
+		case n >= 30 && n <= 37:
+			s.fg = ansiPalette[n-30]
+		case n >= 90 && n <= 97:
+			s.fg = ansiPalette[n-90+8]
+		case n == 39:
+			s.fg = nil
+		case n >= 40 && n <= 47:
+			s.bg = ansiPalette[n-40]
+		case n >= 100 && n <= 107:
+			s.bg = ansiPalette[n-100+8]
+		case n == 49:
+			s.bg = nil
+		case n == 38 || n == 48:
+			// 38;5;n (256색), 38;2;r;g;b (트루컬러)
+			c, used := extendedColor(ps[i+1:])
+			i += used
+			if n == 38 {
+				s.fg = c
+			} else {
+				s.bg = c
+			}
+		}
+	}
+}
+
+func extendedColor(ps []string) (color.Color, int) {
+	num := func(k int) uint8 {
+		if k >= len(ps) {
+			return 0
+		}
+		v, _ := strconv.Atoi(ps[k])
+		return uint8(v)
+	}
+	if len(ps) == 0 {
+		return nil, 0
+	}
+	switch ps[0] {
+	case "5":
+		return xterm256(num(1)), 2
+	case "2":
+		return color.NRGBA{R: num(1), G: num(2), B: num(3), A: 0xff}, 4
+	}
+	return nil, 1
+}
+
+// xterm256 256색 팔레트: 0-15 기본, 16-231 6x6x6 큐브, 232-255 회색
+func xterm256(n uint8) color.Color {
+	switch {
+	case n < 16:
+		return ansiPalette[n]
+	case n < 232:
+		n -= 16
+		level := func(v uint8) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return color.NRGBA{R: level(n / 36), G: level(n / 6 % 6), B: level(n % 6), A: 0xff}
+	default:
+		g := 8 + (n-232)*10
+		return color.NRGBA{R: g, G: g, B: g, A: 0xff}
+	}
+}
+
+// ansiRows SGR 이스케이프를 해석해 TextGrid 행으로. 그 외 CSI 시퀀스는 버린다.
+// 탭은 TextGrid.SetText처럼 다음 탭 위치까지 공백으로 채운다.
+func ansiRows(text string, tabWidth int) []widget.TextGridRow {
+	var (
+		rows  []widget.TextGridRow
+		cells []widget.TextGridCell
+		st    sgrState
+		style widget.TextGridStyle // st.style() 캐시
+	)
+	rs := []rune(text)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '\x1b' && i+1 < len(rs) && rs[i+1] == '[':
+			// CSI: 인자(0x30-0x3f), 중간(0x20-0x2f), 끝 글자(0x40-0x7e)
+			j := i + 2
+			for j < len(rs) && rs[j] >= 0x20 && rs[j] <= 0x3f {
+				j++
+			}
+			if j >= len(rs) {
+				i = len(rs)
+				continue
+			}
+			if rs[j] == 'm' {
+				st.apply(string(rs[i+2 : j]))
+				style = st.style()
+			}
+			i = j
+		case r == '\n':
+			rows = append(rows, widget.TextGridRow{Cells: cells})
+			cells = nil
+		case r == '\r':
+			// 줄 앞으로 (진행률 표시 등): 같은 줄을 덮어쓴다. "\r\n"은 줄바꿈
+			if i+1 < len(rs) && rs[i+1] == '\n' {
+				continue
+			}
+			cells = cells[:0]
+		case r == '\t':
+			cells = append(cells, widget.TextGridCell{Rune: r, Style: style})
+			for next := (len(cells)-1)/tabWidth*tabWidth + tabWidth; len(cells) < next; {
+				cells = append(cells, widget.TextGridCell{Rune: ' ', Style: style})
+			}
+		default:
+			cells = append(cells, widget.TextGridCell{Rune: r, Style: style})
+		}
+	}
+	return append(rows, widget.TextGridRow{Cells: cells})
+}
+
+// setANSIText SetText 대신: 색/스타일을 셀 스타일로 적용
+func setANSIText(g *widget.TextGrid, text string) {
+	tabWidth := g.TabWidth
+	if tabWidth == 0 {
+		tabWidth = 4
+	}
+	g.Rows = ansiRows(text, tabWidth)
+	g.Refresh()
+}
@@ -0,0 +1,360 @@
+package components
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	markColor    = color.NRGBA{R: 0xfc, G: 0xe9, B: 0x4f, A: 0x66} // 검색 결과
+	currentColor = color.NRGBA{R: 0xff, G: 0x8c, B: 0x00, A: 0xbb} // 현재 검색 위치
+)
+
+type gridPos struct{ row, col int }
+
+func (p gridPos) before(o gridPos) bool {
+	return p.row < o.row || p.row == o.row && p.col < o.col
+}
+
+// gridMark 검색 결과 한 개 (한 줄 안)
+type gridMark struct {
+	row, col, n int
+	current     bool
+}
+
+// logGrid 콘솔 텍스트 블록. 드래그로 고른 글자를 클립보드로 복사하고 검색 결과를 칠한다.
+type logGrid struct {
+	widget.TextGrid
+	text  string // ANSI 포함 원문. 하이라이트를 지울 때 다시 그린다.
+	marks []gridMark
+
+	anchor, caret gridPos
+	selected      bool
+	dragging      bool
+
+	onSelect func(g *logGrid) // 드래그 시작 (다른 블록의 선택 해제)
+	onCopy   func(text string)
+	onTap    func()
+}
+
+func newLogGrid() *logGrid {
+	g := &logGrid{}
+	g.ExtendBaseWidget(g)
+	g.Scroll = fyne.ScrollNone
+	return g
+}
+
+func (g *logGrid) setText(text string) {
+	g.text = text
+	g.render()
+}
+
+func (g *logGrid) setMarks(marks []gridMark) {
+	if len(marks) == 0 && len(g.marks) == 0 {
+		return
+	}
+	g.marks = marks
+	g.render()
+}
+
+func (g *logGrid) tabWidth() int {
+	if g.TabWidth == 0 {
+		return 4
+	}
+	return g.TabWidth
+}
+
+// render 원문을 다시 해석하고 검색 결과, 선택 영역 순서로 배경을 덧칠한다
+func (g *logGrid) render() {
+	g.Rows = ansiRows(g.text, g.tabWidth())
+	for _, m := range g.marks {
+		bg := color.Color(markColor)
+		if m.current {
+			bg = currentColor
+		}
+		g.paintRange(gridPos{m.row, m.col}, gridPos{m.row, m.col + m.n}, bg)
+	}
+	if g.selected {
+		from, to := g.selection()
+		g.paintRange(from, to, theme.Color(theme.ColorNameSelection))
+	}
+	g.Refresh()
+}
+
+// paintRange [from, to) 셀의 배경만 바꾼다 (글자색과 굵기는 유지)
+func (g *logGrid) paintRange(from, to gridPos, bg color.Color) {
+	for r := from.row; r <= to.row && r < len(g.Rows); r++ {
+		cells := g.Rows[r].Cells
+		start, end := 0, len(cells)
+		if r == from.row {
+			start = from.col
+		}
+		if r == to.row {
+			end = min(end, to.col)
+		}
+		for i := start; i < end; i++ {
+			st := &widget.CustomTextGridStyle{BGColor: bg}
+			if old := cells[i].Style; old != nil {
+				st.TextStyle, st.FGColor = old.Style(), old.TextColor()
+			}
+			cells[i].Style = st
+		}
+	}
+}
+
+// selection 앞뒤를 정리한 선택 영역 [from, to)
+func (g *logGrid) selection() (gridPos, gridPos) {
+	from, to := g.anchor, g.caret
+	if to.before(from) {
+		from, to = to, from
+	}
+	to.col++
+	return from, to
+}
+
+// selectedText 탭 뒤에 채운 공백은 빼고 원래 탭으로
+func (g *logGrid) selectedText() string {
+	from, to := g.selection()
+	tw := g.tabWidth()
+	var sb strings.Builder
+	for r := from.row; r <= to.row && r < len(g.Rows); r++ {
+		if r > from.row {
+			sb.WriteByte('\n')
+		}
+		cells := g.Rows[r].Cells
+		end := len(cells)
+		if r == to.row {
+			end = min(end, to.col)
+		}
+		i := 0
+		if r == from.row {
+			i = from.col
+		}
+		for ; i < end; i++ {
+			sb.WriteRune(cells[i].Rune)
+			if cells[i].Rune == '\t' {
+				for next := i/tw*tw + tw; i+1 < next && i+1 < end && cells[i+1].Rune == ' '; {
+					i++
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+func (g *logGrid) Dragged(e *fyne.DragEvent) {
+	if !g.dragging {
+		start := e.Position.Subtract(e.Dragged)
+		g.anchor.row, g.anchor.col = g.CursorLocationForPosition(start)
+		g.selected, g.dragging = true, true
+		if g.onSelect != nil {
+			g.onSelect(g)
+		}
+	}
+	g.caret.row, g.caret.col = g.CursorLocationForPosition(e.Position)
+	g.render()
+}
+
+func (g *logGrid) DragEnd() {
+	g.dragging = false
+	if g.selected && g.onCopy != nil {
+		g.onCopy(g.selectedText())
+	}
+	if g.onTap != nil {
+		g.onTap()
+	}
+}
+
+// Tapped 선택 해제
+func (g *logGrid) Tapped(*fyne.PointEvent) {
+	g.unselect()
+	if g.onTap != nil {
+		g.onTap()
+	}
+}
+
+func (g *logGrid) unselect() {
+	if g.selected {
+		g.selected = false
+		g.render()
+	}
+}
+
+// searchHit 검색 결과 위치
+type searchHit struct {
+	grid *logGrid
+	row  int
+	col  int
+}
+
+// consoleSearch Ctrl+F 검색 막대
+type consoleSearch struct {
+	cs    *Console
+	bar   *fyne.Container
+	entry *searchEntry
+	count *widget.Label
+
+	query []rune
+	hits  []searchHit
+	cur   int
+}
+
+func newConsoleSearch(cs *Console, onClose func()) *consoleSearch {
+	s := &consoleSearch{cs: cs, count: widget.NewLabel("")}
+	s.entry = newSearchEntry()
+	s.entry.SetPlaceHolder("search")
+	s.entry.OnChanged = func(q string) { s.find(q) }
+	s.entry.OnSubmitted = func(string) { s.move(1) }
+	s.entry.onPrev = func() { s.move(-1) }
+	s.entry.onNext = func() { s.move(1) }
+	s.entry.onClose = onClose
+
+	prev := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { s.move(-1) })
+	next := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { s.move(1) })
+	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), onClose)
+	s.bar = container.NewBorder(nil, nil, nil, container.NewHBox(s.count, prev, next, closeBtn), s.entry)
+	s.bar.Hide()
+	return s
+}
+
+func (s *consoleSearch) active() bool { return s.bar.Visible() }
+
+// find 보이는 텍스트 블록 전체에서 대소문자 무시로 찾는다. 현재 위치는 가장 최근(아래) 결과.
+func (s *consoleSearch) find(q string) {
+	s.query = []rune(strings.ToLower(q))
+	s.hits = nil
+	byGrid := map[*logGrid][]gridMark{}
+	for _, obj := range s.cs.objs {
+		g, ok := obj.(*logGrid)
+		if !ok {
+			continue
+		}
+		if len(s.query) > 0 {
+			for r, row := range g.Rows {
+				for _, col := range indexRunes(row.Cells, s.query) {
+					s.hits = append(s.hits, searchHit{grid: g, row: r, col: col})
+					byGrid[g] = append(byGrid[g], gridMark{row: r, col: col, n: len(s.query)})
+				}
+			}
+		}
+		g.setMarks(byGrid[g])
+	}
+	s.cur = len(s.hits) - 1
+	s.show()
+}
+
+// refind 출력이 바뀌었을 때 같은 검색어로 다시
+func (s *consoleSearch) refind() {
+	if s.active() && len(s.query) > 0 {
+		s.find(string(s.query))
+	}
+}
+
+func (s *consoleSearch) move(d int) {
+	if len(s.hits) == 0 {
+		return
+	}
+	s.cur = (s.cur + d + len(s.hits)) % len(s.hits)
+	s.show()
+}
+
+// show 현재 결과만 다른 색으로 칠하고 화면 가운데로 스크롤
+func (s *consoleSearch) show() {
+	if len(s.hits) == 0 {
+		if len(s.query) == 0 {
+			s.count.SetText("")
+		} else {
+			s.count.SetText("0/0")
+		}
+		return
+	}
+	s.count.SetText(fmt.Sprintf("%d/%d", s.cur+1, len(s.hits)))
+
+	hit := s.hits[s.cur]
+	for _, g := range s.markedGrids() {
+		marks := g.marks
+		for i := range marks {
+			marks[i].current = g == hit.grid && marks[i].row == hit.row && marks[i].col == hit.col
+		}
+		g.setMarks(marks)
+	}
+
+	y := hit.grid.Position().Y + hit.grid.PositionForCursorLocation(hit.row, 0).Y
+	s.cs.scroll.ScrollToOffset(fyne.NewPos(0, y-s.cs.scroll.Size().Height/2))
+}
+
+func (s *consoleSearch) markedGrids() []*logGrid {
+	var gs []*logGrid
+	for _, h := range s.hits {
+		if len(gs) == 0 || gs[len(gs)-1] != h.grid {
+			gs = append(gs, h.grid)
+		}
+	}
+	return gs
+}
+
+// clear 하이라이트 지우기
+func (s *consoleSearch) clear() {
+	for _, g := range s.markedGrids() {
+		g.setMarks(nil)
+	}
+	s.hits, s.query = nil, nil
+	s.count.SetText("")
+}
+
+// indexRunes 한 줄에서 q가 나오는 열 (대소문자 무시, 겹치지 않게)
+func indexRunes(cells []widget.TextGridCell, q []rune) []int {
+	var cols []int
+	for i := 0; i+len(q) <= len(cells); i++ {
+		j := 0
+		for j < len(q) && unicode.ToLower(cells[i+j].Rune) == q[j] {
+			j++
+		}
+		if j == len(q) {
+			cols = append(cols, i)
+			i += len(q) - 1
+		}
+	}
+	return cols
+}
+
+// searchEntry Enter/↓ 다음, ↑ 이전, Esc 닫기
+type searchEntry struct {
+	widget.Entry
+	onPrev  func()
+	onNext  func()
+	onClose func()
+}
+
+func newSearchEntry() *searchEntry {
+	e := &searchEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *searchEntry) TypedKey(k *fyne.KeyEvent) {
+	switch k.Name {
+	case fyne.KeyEscape:
+		e.onClose()
+	case fyne.KeyUp:
+		e.onPrev()
+	case fyne.KeyDown:
+		e.onNext()
+	default:
+		e.Entry.TypedKey(k)
+	}
+}
+
+// isFind Ctrl+F (macOS는 Cmd+F)
+func isFind(s fyne.Shortcut) bool {
+	sc, ok := s.(*desktop.CustomShortcut)
+	return ok && sc.KeyName == fyne.KeyF && sc.Modifier == fyne.KeyModifierShortcutDefault
+}
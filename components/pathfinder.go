@@ -1,8 +1,9 @@
 package components
 
 import (
+	"context"
+	"io/fs"
 	"log/slog"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/meteormin/minder/commands"
+	"github.com/meteormin/minder/vfs"
 )
 
 type FileTree struct {
@@ -20,10 +23,13 @@ type FileTree struct {
 	rootDir    binding.String
 	showHidden binding.Bool
 	win        fyne.Window
+	fsys       vfs.ReadFS
 	onSelected func(string)
+	onDirOpen  func(string)
 
 	open   map[string]struct{} // ★ 현재 열려 있는 브랜치 집합
 	unsubs []func()            // 바인딩 리스너 해제
+	watch  *dirWatcher         // 열린 브랜치를 지켜본다 (다른 프로그램이 바꾼 것도 보이게)
 }
 
 type FileTreeConfig struct {
@@ -31,16 +37,26 @@ type FileTreeConfig struct {
 	RootDir    binding.String
 	ShowHidden binding.Bool
 	OnSelected func(uid string)
+	OnDirOpen  func(uid string) // 브랜치를 열 때 (방문 기록용)
+	FS         vfs.ReadFS       // 트리가 보여 줄 파일 시스템. 없으면 vfs.OS
+	// OnWatchLimit 감시 한도에 걸려 자동 갱신이 안 되는 브랜치가 생기거나 없어질 때 (UI 스레드)
+	OnWatchLimit func(limited bool)
 }
 
 func NewFileTreeWithData(cfg FileTreeConfig) (*FileTree, error) {
+	if cfg.FS == nil {
+		cfg.FS = vfs.OS
+	}
 	ft := &FileTree{
 		rootDir:    cfg.RootDir,
 		showHidden: cfg.ShowHidden,
 		win:        cfg.Window,
+		fsys:       cfg.FS,
 		onSelected: cfg.OnSelected,
+		onDirOpen:  cfg.OnDirOpen,
 		open:       map[string]struct{}{}, // ★
 	}
+	ft.watch = newDirWatcher(ft.fsys, ft.refreshChanged, cfg.OnWatchLimit)
 
 	// 콜백 기반 Tree
 	ft.Tree = widget.NewTree(
@@ -58,6 +74,7 @@ func NewFileTreeWithData(cfg FileTreeConfig) (*FileTree, error) {
 
 	ft.Tree.Root = root
 	ft.open[root] = struct{}{} // ★ 루트를 열린 것으로 간주
+	ft.watch.add(root)
 	ft.Tree.OpenBranch(root)
 	ft.Tree.OnSelected = func(uid string) {
 		ft.Tree.UnselectAll()
@@ -72,20 +89,19 @@ func NewFileTreeWithData(cfg FileTreeConfig) (*FileTree, error) {
 			return
 		}
 
-		info, err := os.Lstat(uid)
+		info, err := ft.fsys.Lstat(uid)
 		if err != nil {
 			dialog.ShowError(err, ft.win)
 			return
 		}
 
 		isDir := info.IsDir()
-		if !isDir && info.Mode()&os.ModeSymlink != 0 {
-			if ti, err := os.Stat(uid); err == nil && ti.IsDir() {
-				isDir = true
-			}
+		if !isDir && info.Mode()&fs.ModeSymlink != 0 {
+			isDir = vfs.IsDir(ft.fsys, uid)
 		}
 
-		if isDir {
+		// 아카이브는 안쪽을 브랜치로 펼친다
+		if isDir || vfs.IsArchiveDir(ft.fsys, uid) {
 			if ft.Tree.IsBranchOpen(uid) {
 				ft.Tree.CloseBranch(uid)
 			} else {
@@ -101,10 +117,15 @@ func NewFileTreeWithData(cfg FileTreeConfig) (*FileTree, error) {
 	// 브랜치 토글 시 아이콘 새로고침
 	ft.Tree.OnBranchOpened = func(uid string) {
 		ft.open[uid] = struct{}{} // ★
-		ft.Tree.RefreshItem(uid)  // 아이콘/자식 목록 갱신
+		ft.watch.add(uid)
+		ft.Tree.RefreshItem(uid) // 아이콘/자식 목록 갱신
+		if ft.onDirOpen != nil && !vfs.IsArchiveDir(ft.fsys, uid) {
+			ft.onDirOpen(uid)
+		}
 	}
 	ft.Tree.OnBranchClosed = func(uid string) {
 		delete(ft.open, uid) // ★
+		ft.watch.remove(uid)
 		ft.Tree.RefreshItem(uid)
 	}
 
@@ -119,6 +140,35 @@ func (ft *FileTree) Refresh() {
 	ft.RefreshItem(ft.Root)
 }
 
+// refreshChanged 지켜보던 브랜치가 바뀌면 자식 목록만 다시 읽는다.
+// 숨김 파일만 바뀌었고 숨김 파일을 안 보이는 중이면 건너뛴다.
+func (ft *FileTree) refreshChanged(changes map[string][]string) {
+	showHidden, _ := ft.showHidden.Get()
+	refreshed := false
+	for dir, names := range changes {
+		if _, ok := ft.open[dir]; !ok {
+			continue
+		}
+		if !showHidden && len(names) > 0 && allHidden(names) {
+			continue
+		}
+		ft.Tree.RefreshItem(dir)
+		refreshed = true
+	}
+	if refreshed {
+		ft.Tree.Refresh()
+	}
+}
+
+func allHidden(names []string) bool {
+	for _, n := range names {
+		if !strings.HasPrefix(n, ".") {
+			return false
+		}
+	}
+	return true
+}
+
 // SetRootDir 외부에서 문자열로 루트 바꾸고 싶을 때도 binding으로 통일
 func (ft *FileTree) SetRootDir(root string) {
 	if root == "" {
@@ -179,7 +229,7 @@ func (ft *FileTree) childUIDs(uid string) []string {
 		path = uid
 	}
 
-	entries, err := os.ReadDir(path)
+	entries, err := ft.fsys.ReadDir(path)
 	if err != nil {
 		fyne.LogError("read dir failed "+path, err)
 		return nil
@@ -202,10 +252,8 @@ func (ft *FileTree) childUIDs(uid string) []string {
 		isDir := e.IsDir()
 		// 심볼릭 링크가 디렉터리를 가리키면 디렉터리로 간주
 		if !isDir {
-			if info, err := os.Lstat(full); err == nil && info.Mode()&os.ModeSymlink != 0 {
-				if ti, err := os.Stat(full); err == nil && ti.IsDir() {
-					isDir = true
-				}
+			if info, err := ft.fsys.Lstat(full); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+				isDir = vfs.IsDir(ft.fsys, full)
 			}
 		}
 		tmp = append(tmp, item{name: name, path: full, dir: isDir})
@@ -230,17 +278,15 @@ func (ft *FileTree) isBranch(uid string) bool {
 	if uid == "" {
 		return true
 	}
-	if info, err := os.Lstat(uid); err == nil {
+	if info, err := ft.fsys.Lstat(uid); err == nil {
 		if info.IsDir() {
 			return true
 		}
-		if info.Mode()&os.ModeSymlink != 0 {
-			if ti, err := os.Stat(uid); err == nil && ti.IsDir() {
-				return true
-			}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return vfs.IsDir(ft.fsys, uid)
 		}
 	}
-	return false
+	return vfs.IsArchiveDir(ft.fsys, uid)
 }
 
 func (ft *FileTree) createItem(branch bool) fyne.CanvasObject {
@@ -272,12 +318,13 @@ func (ft *FileTree) updateItem(uid string, branch bool, obj fyne.CanvasObject) {
 	}
 }
 
-// Destroy 필요 시 호출: 바인딩 리스너 해제
+// Destroy 필요 시 호출: 바인딩 리스너와 감시 해제
 func (ft *FileTree) Destroy() {
 	for _, u := range ft.unsubs {
 		u()
 	}
 	ft.unsubs = nil
+	ft.watch.close()
 }
 
 type PathfinderState struct {
@@ -288,6 +335,13 @@ type PathfinderState struct {
 type Pathfinder struct {
 	State     PathfinderState
 	Container *fyne.Container
+
+	tree *FileTree
+}
+
+// Destroy 사이드바를 다시 그리기 전에: 트리의 리스너와 감시 해제
+func (pf *Pathfinder) Destroy() {
+	pf.tree.Destroy()
 }
 
 type PathfinderConfig struct {
@@ -297,6 +351,11 @@ type PathfinderConfig struct {
 
 func NewPathfinder(cfg PathfinderConfig) *Pathfinder {
 	label := widget.NewLabelWithData(cfg.RootDir)
+	// 감시 한도 표시는 트리 아래에
+	limit := newWatchLimitLabel()
+	if cfg.OnWatchLimit == nil {
+		cfg.OnWatchLimit = showWatchLimit(limit)
+	}
 	fTree, err := NewFileTreeWithData(cfg.FileTreeConfig)
 	if err != nil {
 		cfg.Logger.Error("failed new file tree", "err", err)
@@ -311,8 +370,11 @@ func NewPathfinder(cfg PathfinderConfig) *Pathfinder {
 	})
 
 	topBox := container.NewVBox(label, check)
+	if mux, ok := cfg.FS.(*vfs.Mux); ok {
+		topBox.Add(newRootSelect(cfg, mux).Select)
+	}
 
-	c := container.NewBorder(topBox, nil, nil, nil, fTree)
+	c := container.NewBorder(topBox, limit, nil, nil, fTree)
 
 	return &Pathfinder{
 		State: PathfinderState{
@@ -320,5 +382,114 @@ func NewPathfinder(cfg PathfinderConfig) *Pathfinder {
 			ShowHidden: cfg.ShowHidden,
 		},
 		Container: c,
+		tree:      fTree,
 	}
 }
+
+// localRoot 루트 선택에서 로컬 디스크
+const localRoot = "local"
+
+// rootSelect 트리 루트를 로컬과 원격 저장소(마운트 지점) 사이에서 고른다.
+// 연결되지 않은 원격을 고르면 설정(remotes.json)대로 연결한다.
+type rootSelect struct {
+	*widget.Select
+	cfg     PathfinderConfig
+	mux     *vfs.Mux
+	remotes map[string]commands.Remote // 마운트 지점 → 설정
+	local   string                     // 마지막으로 본 로컬 루트
+	syncing bool                       // 루트에 맞춰 고치는 중 (OnChanged 무시)
+}
+
+func newRootSelect(cfg PathfinderConfig, mux *vfs.Mux) *rootSelect {
+	rs := &rootSelect{cfg: cfg, mux: mux}
+	rs.local, _ = cfg.RootDir.Get()
+	rs.Select = widget.NewSelect(nil, rs.choose)
+	rs.reload()
+
+	cfg.RootDir.AddListener(binding.NewDataListener(func() {
+		fyne.Do(rs.sync)
+	}))
+	mux.Notify(func() {
+		fyne.Do(func() {
+			// 보고 있던 원격이 끊겼으면 로컬로
+			if root, _ := cfg.RootDir.Get(); rs.Selected != localRoot && rs.mountOf(root) == "" {
+				_ = cfg.RootDir.Set(rs.local)
+			}
+			rs.reload()
+		})
+	})
+	return rs
+}
+
+// reload 설정된 원격과 지금 붙어 있는 마운트로 목록을 다시 만든다
+func (rs *rootSelect) reload() {
+	remotes, err := commands.LoadRemotes()
+	if err != nil {
+		rs.cfg.Logger.Error("failed load remotes", "err", err)
+	}
+	rs.remotes = map[string]commands.Remote{}
+	opts := []string{localRoot}
+	for _, r := range remotes {
+		rs.remotes[r.MountPoint()] = r
+		opts = append(opts, r.MountPoint())
+	}
+	for _, m := range rs.mux.Mounts() {
+		if _, ok := rs.remotes[m.Dir]; !ok {
+			opts = append(opts, m.Dir)
+		}
+	}
+	rs.Options = opts
+	rs.sync()
+}
+
+// mountOf root가 속한 마운트 지점 (로컬이면 "")
+func (rs *rootSelect) mountOf(root string) string {
+	for _, m := range rs.mux.Mounts() {
+		if root == m.Dir || strings.HasPrefix(root, m.Dir+string(filepath.Separator)) {
+			return m.Dir
+		}
+	}
+	return ""
+}
+
+// sync 트리 루트에 맞춰 선택을 고친다
+func (rs *rootSelect) sync() {
+	root, _ := rs.cfg.RootDir.Get()
+	cur := rs.mountOf(root)
+	if cur == "" {
+		rs.local = root
+		cur = localRoot
+	}
+	rs.syncing = true
+	rs.SetSelected(cur)
+	rs.syncing = false
+}
+
+func (rs *rootSelect) choose(opt string) {
+	if rs.syncing {
+		return
+	}
+	if opt == localRoot {
+		_ = rs.cfg.RootDir.Set(rs.local)
+		return
+	}
+	r, ok := rs.remotes[opt]
+	if !ok {
+		// 설정에 없는 마운트 (connect <url>)
+		_ = rs.cfg.RootDir.Set(opt)
+		return
+	}
+
+	// 연결은 네트워크를 타므로 UI 스레드 밖에서
+	go func() {
+		dir, err := commands.Connect(context.Background(), rs.mux, r)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, rs.cfg.Window)
+				rs.sync()
+				return
+			}
+			_ = rs.cfg.RootDir.Set(dir)
+		})
+	}()
+}
@@ -11,8 +11,8 @@ import (
 
 	"io"
 	"log/slog"
-	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"fyne.io/fyne/v2/data/binding"
@@ -26,14 +26,18 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	markdown "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/meteormin/minder/vfs"
 )
 
 type Previewer struct {
 	logger            *slog.Logger
+	fsys              vfs.FS
 	maxTextRenderSize int64
+	editing           bool // 편집 모드 (파일이 바뀌어도 다시 읽지 않는다)
 }
 
 func (p *Previewer) RenderFile(path string) (fyne.CanvasObject, error) {
+	p.editing = false
 	if path == "" {
 		return container.NewPadded(container.NewCenter(widget.NewLabel("no file selected"))), nil
 	}
@@ -62,31 +66,44 @@ func (p *Previewer) RenderFile(path string) (fyne.CanvasObject, error) {
 	return widget.NewCard(filepath.Base(path), ext, rendered), err
 }
 
+// imageSniffSize 이미지 포맷을 판별할 때 읽는 앞부분 (JPEG EXIF 포함)
+const imageSniffSize = 256 << 10
+
 func (p *Previewer) asImageReader(path string) (io.Reader, error) {
-	data, err := os.ReadFile(path)
+	f, err := p.fsys.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer func(f vfs.File) {
+		if fErr := f.Close(); fErr != nil {
+			p.logger.Error("failed close file", "err", fErr)
+		}
+	}(f)
 
-	r := bytes.NewReader(data)
-	// 시그니처만 읽어 포맷 판별
-	_, format, err := image.DecodeConfig(r)
+	// 앞부분만으로 포맷 판별. 이미지가 아니면 (원격의 큰 파일도) 끝까지 받지 않는다
+	head := make([]byte, imageSniffSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	_, format, err := image.DecodeConfig(bytes.NewReader(head))
 	if err != nil {
 		return nil, err
 	}
 	if format == "" {
 		return nil, errors.New("invalid image format")
 	}
-	_, err = r.Seek(0, 0)
+
+	rest, err := io.ReadAll(f)
 	if err != nil {
 		return nil, err
 	}
-
-	return r, nil
+	return io.MultiReader(bytes.NewReader(head), bytes.NewReader(rest)), nil
 }
 
 func (p *Previewer) isTextRenderable(path string) bool {
-	s, err := os.Stat(path)
+	s, err := p.fsys.Stat(path)
 	if err != nil || s.IsDir() {
 		return false
 	}
@@ -94,11 +111,11 @@ func (p *Previewer) isTextRenderable(path string) bool {
 		return false
 	}
 
-	f, err := os.Open(path)
+	f, err := p.fsys.Open(path)
 	if err != nil {
 		return false
 	}
-	defer func(f *os.File) {
+	defer func(f vfs.File) {
 		fErr := f.Close()
 		if fErr != nil {
 			p.logger.Error("failed close file", "err", fErr)
@@ -122,7 +139,7 @@ func (p *Previewer) renderImage(r io.Reader, path string) (fyne.CanvasObject, er
 }
 
 func (p *Previewer) renderSmartText(path string) (fyne.CanvasObject, error) {
-	st, err := os.Stat(path)
+	st, err := p.fsys.Stat(path)
 	if err != nil {
 		return container.NewCenter(widget.NewLabel(err.Error())), err
 	}
@@ -130,7 +147,7 @@ func (p *Previewer) renderSmartText(path string) (fyne.CanvasObject, error) {
 		return container.NewCenter(widget.NewLabel("this is a directory")), nil
 	}
 
-	origBytes, err := os.ReadFile(path)
+	origBytes, err := vfs.ReadFile(p.fsys, path)
 	if err != nil {
 		return container.NewCenter(widget.NewLabel(err.Error())), err
 	}
@@ -193,13 +210,14 @@ func (p *Previewer) renderSmartText(path string) (fyne.CanvasObject, error) {
 	saveBtn := widget.NewButtonWithIcon("save", theme.DocumentSaveIcon(), func() {
 		newText := editor.Text
 		// 저장
-		if writeErr := os.WriteFile(path, []byte(newText), 0644); writeErr != nil {
+		if writeErr := vfs.WriteFile(p.fsys, path, []byte(newText), 0644); writeErr != nil {
 			if p.logger != nil {
 				p.logger.Error("failed save file", "err", writeErr)
 			}
 			return
 		}
 		// 리렌더 후 보기 모드로 복귀
+		p.editing = false
 		newView := buildViewer(newText)
 		stack.Objects[0] = newView
 		stack.Objects[1].Hide()
@@ -242,6 +260,7 @@ func (p *Previewer) renderSmartText(path string) (fyne.CanvasObject, error) {
 		editBar.Show()
 	}
 	editBtn.OnTapped = func() { // 버튼 핸들러 교체(동일 동작)
+		p.editing = true
 		stack.Objects[0].Hide()
 		stack.Objects[1].Show()
 		editor.Enable()
@@ -251,6 +270,7 @@ func (p *Previewer) renderSmartText(path string) (fyne.CanvasObject, error) {
 
 	// 위에서 정의됨
 	cancelBtn.OnTapped = func() {
+		p.editing = false
 		editor.SetText(origText)
 		stack.Objects[1].Hide()
 		stack.Objects[0].Show()
@@ -271,12 +291,18 @@ type PreviewPane struct {
 
 	unsub func() // 바인딩 해제용
 	cur   string
+
+	watch *dirWatcher // cur가 든 디렉터리를 지켜본다 (다른 프로그램이 바꾸면 다시 읽기)
 }
 
 func NewPreviewPane(p *Previewer) *PreviewPane {
 	st := container.NewStack() // 초기엔 비움
-	root := container.NewPadded(st)
-	return &PreviewPane{p: p, stack: st, root: root}
+	limit := newWatchLimitLabel()
+	root := container.NewPadded(container.NewBorder(nil, limit, nil, nil, st))
+	v := &PreviewPane{p: p, stack: st, root: root}
+	// 편집기처럼 새 파일로 바꿔 쓰는 저장도 잡도록 파일이 아니라 디렉터리를 본다
+	v.watch = newDirWatcher(p.fsys, v.reloadChanged, showWatchLimit(limit))
+	return v
 }
 
 func (v *PreviewPane) Root() fyne.CanvasObject { return v.root }
@@ -286,8 +312,26 @@ func (v *PreviewPane) SetPath(path string) {
 	if path == "" || path == v.cur {
 		return
 	}
+	if v.cur != "" {
+		v.watch.remove(filepath.Dir(v.cur))
+	}
 	v.cur = path
+	v.watch.add(filepath.Dir(path))
+	v.render(path)
+}
 
+// reloadChanged 보고 있는 파일이 바뀌었으면 다시 그린다. 편집 중이면 그대로 둔다
+func (v *PreviewPane) reloadChanged(changes map[string][]string) {
+	if v.cur == "" || v.p.editing {
+		return
+	}
+	names, ok := changes[filepath.Dir(v.cur)]
+	if ok && (len(names) == 0 || slices.Contains(names, filepath.Base(v.cur))) {
+		v.render(v.cur)
+	}
+}
+
+func (v *PreviewPane) render(path string) {
 	co, err := v.p.RenderFile(path) // 당신의 기존 함수 재사용
 	if co == nil {
 		co = container.NewCenter(widget.NewLabel(err.Error()))
@@ -328,9 +372,16 @@ func (v *PreviewPane) Unbind() {
 	}
 }
 
+// Destroy 다시 만들기 전이나 창을 닫을 때: 바인딩과 감시 해제
+func (v *PreviewPane) Destroy() {
+	v.Unbind()
+	v.watch.close()
+}
+
 type PreviewConfig struct {
 	Logger *slog.Logger
 	Path   binding.String
+	FS     vfs.FS // 없으면 vfs.OS
 }
 
 type Preview struct {
@@ -339,8 +390,12 @@ type Preview struct {
 }
 
 func NewPreview(cfg PreviewConfig) *Preview {
+	if cfg.FS == nil {
+		cfg.FS = vfs.OS
+	}
 	p := &Previewer{
 		logger:            cfg.Logger,
+		fsys:              cfg.FS,
 		maxTextRenderSize: 1024 * 1024, // 1MB
 	}
 
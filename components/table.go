@@ -0,0 +1,122 @@
+package components
+
+import (
+	"image/color"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/meteormin/minder/commands"
+)
+
+const (
+	tableMaxVisibleRows = 15  // 이보다 길면 표 안에서 스크롤
+	tableMeasureRows    = 200 // 열 너비 계산에 보는 행 수
+)
+
+// newTableBlock 명령이 돌려준 표를 콘솔에 넣을 widget.Table로.
+// 헤더를 누르면 그 열로 정렬(다시 누르면 역순), 경로 칸을 누르면 onOpen.
+func newTableBlock(t *commands.Table, onOpen func(p string)) fyne.CanvasObject {
+	order := make([]int, len(t.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	sortCol, desc := -1, false
+
+	var tbl *widget.Table
+	tbl = widget.NewTableWithHeaders(
+		func() (int, int) { return len(order), len(t.Columns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			l := o.(*widget.Label)
+			l.SetText(t.Cell(order[id.Row], id.Col))
+
+			kind := t.Columns[id.Col].Kind
+			l.Alignment = fyne.TextAlignLeading
+			if kind == commands.ColSize {
+				l.Alignment = fyne.TextAlignTrailing
+			}
+			l.Importance = widget.MediumImportance
+			l.TextStyle = fyne.TextStyle{}
+			if _, ok := t.Path(order[id.Row], id.Col); ok {
+				// 누를 수 있는 칸 표시, 디렉터리는 굵게 (명령이 표를 만들 때 적어 둔 것)
+				l.Importance = widget.HighImportance
+				l.TextStyle.Bold = t.IsDir(order[id.Row])
+			}
+			l.Refresh()
+		},
+	)
+	tbl.ShowHeaderColumn = false
+	tbl.CreateHeader = func() fyne.CanvasObject {
+		b := widget.NewButton("", nil)
+		b.Alignment = widget.ButtonAlignLeading
+		b.Importance = widget.LowImportance
+		return b
+	}
+	tbl.UpdateHeader = func(id widget.TableCellID, o fyne.CanvasObject) {
+		b := o.(*widget.Button)
+		if id.Col < 0 {
+			b.SetText("")
+			b.OnTapped = nil
+			return
+		}
+		b.SetText(headerText(t.Columns[id.Col].Name, id.Col == sortCol, desc))
+		col := id.Col
+		b.OnTapped = func() {
+			if sortCol == col {
+				desc = !desc
+			} else {
+				sortCol, desc = col, false
+			}
+			sort.SliceStable(order, func(i, j int) bool {
+				if desc {
+					return t.Less(col, order[j], order[i])
+				}
+				return t.Less(col, order[i], order[j])
+			})
+			tbl.Refresh()
+		}
+	}
+	tbl.OnSelected = func(id widget.TableCellID) {
+		tbl.UnselectAll()
+		if id.Row < 0 || id.Row >= len(order) {
+			return
+		}
+		if p, ok := t.Path(order[id.Row], id.Col); ok && onOpen != nil {
+			onOpen(p)
+		}
+	}
+
+	// 열 너비: 헤더와 앞쪽 행 중 가장 긴 글자
+	pad := theme.Padding() * 4
+	for c, col := range t.Columns {
+		w := fyne.MeasureText(headerText(col.Name, true, false), theme.TextSize(), fyne.TextStyle{Bold: true}).Width
+		for r := 0; r < len(t.Rows) && r < tableMeasureRows; r++ {
+			w = max(w, fyne.MeasureText(t.Cell(r, c), theme.TextSize(), fyne.TextStyle{}).Width)
+		}
+		tbl.SetColumnWidth(c, w+pad)
+	}
+
+	// VBox 안에서는 표가 한 칸 크기로 줄어드므로 높이를 잡아 준다
+	rowH := widget.NewLabel("X").MinSize().Height + theme.SeparatorThicknessSize()
+	rows := min(len(t.Rows), tableMaxVisibleRows) + 1
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(0, rowH*float32(rows)))
+
+	return container.NewStack(spacer, tbl)
+}
+
+func headerText(name string, sorted, desc bool) string {
+	text := strings.ToUpper(name)
+	if !sorted {
+		return text
+	}
+	if desc {
+		return text + " ▼"
+	}
+	return text + " ▲"
+}
@@ -0,0 +1,156 @@
+package components
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"github.com/meteormin/minder/vfs"
+)
+
+type TerminalState struct {
+	Input binding.String
+}
+
+type TerminalConfig struct {
+	Context        context.Context // 명령 실행의 부모 context (창 수명)
+	Pwd            binding.String  // 파일 트리의 루트. 연결된 탭의 cd가 옮긴다.
+	Selected       binding.String
+	Input          binding.String // 첫 탭의 입력
+	Logger         *slog.Logger
+	Window         fyne.Window
+	RefreshSideBar func()
+	Scrollback     int          // 콘솔에 남길 줄 수. 0이면 defaultScrollback, 음수면 무제한
+	FS             vfs.FS       // 명령이 다루는 파일 시스템. 없으면 vfs.OS
+	ShowHidden     binding.Bool // 숨김 파일 표시 설정 (zip, tar가 따른다)
+}
+
+// Terminal 탭으로 나뉜 터미널 세션들. 한 번에 한 탭만 파일 트리와 연결된다.
+type Terminal struct {
+	State     TerminalState
+	Container fyne.CanvasObject
+
+	config   TerminalConfig
+	tabs     *container.DocTabs
+	sessions map[*container.TabItem]*session
+
+	mu     sync.Mutex
+	linked *session
+}
+
+func NewTerminal(config TerminalConfig) *Terminal {
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+	if config.FS == nil {
+		config.FS = vfs.OS
+	}
+	t := &Terminal{
+		State: TerminalState{
+			Input: config.Input,
+		},
+		config:   config,
+		sessions: map[*container.TabItem]*session{},
+	}
+
+	t.tabs = container.NewDocTabs()
+	t.tabs.CreateTab = func() *container.TabItem {
+		return t.addSession(binding.NewString()).item
+	}
+	t.tabs.CloseIntercept = t.closeTab
+	t.tabs.OnSelected = func(item *container.TabItem) {
+		if s := t.sessions[item]; s != nil {
+			s.focus()
+		}
+	}
+
+	// 첫 탭은 트리와 연결된 채로 시작
+	first := t.addSession(config.Input)
+	t.tabs.Append(first.item)
+	t.linked = first
+	first.link.Checked = true
+	first.item.Icon = theme.FolderOpenIcon()
+
+	t.Container = t.tabs
+	return t
+}
+
+// addSession 새 세션: 지금 보고 있는 탭(없으면 트리)의 디렉터리에서 시작
+func (t *Terminal) addSession(input binding.String) *session {
+	from := t.config.Pwd
+	if cur := t.sessions[t.tabs.Selected()]; cur != nil {
+		from = cur.ctx.Pwd
+	}
+	pwd := binding.NewString()
+	if dir, err := from.Get(); err == nil {
+		_ = pwd.Set(dir)
+	}
+
+	s := newSession(t, pwd, input)
+	s.item = container.NewTabItem(s.title(), s.content)
+	t.sessions[s.item] = s
+	return s
+}
+
+// newTab Ctrl+T
+func (t *Terminal) newTab() {
+	s := t.addSession(binding.NewString())
+	t.tabs.Append(s.item)
+	t.tabs.Select(s.item)
+}
+
+// closeTab 마지막 탭은 닫지 않는다
+func (t *Terminal) closeTab(item *container.TabItem) {
+	s := t.sessions[item]
+	if s == nil || len(t.tabs.Items) <= 1 {
+		return
+	}
+	if t.isLinked(s) {
+		t.setLinked(nil)
+	}
+	s.cancel()
+	delete(t.sessions, item)
+	t.tabs.Remove(item)
+}
+
+func (t *Terminal) isLinked(s *session) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.linked == s
+}
+
+// setLinked s만 트리와 연결 (nil이면 연결 해제). UI 스레드에서 호출한다.
+func (t *Terminal) setLinked(s *session) {
+	t.mu.Lock()
+	old := t.linked
+	t.linked = s
+	t.mu.Unlock()
+
+	if old != nil && old != s {
+		old.link.SetChecked(false)
+		old.item.Icon = nil
+	}
+	if s != nil {
+		s.item.Icon = theme.FolderOpenIcon()
+		t.syncTree(s)
+	}
+	t.tabs.Refresh()
+}
+
+// syncTree 명령이 파일이나 디렉터리를 바꾼 뒤. 연결된 탭이면 트리 루트도 그 탭의 디렉터리로 옮긴다.
+func (t *Terminal) syncTree(s *session) {
+	if t.isLinked(s) {
+		if pwd, err := s.ctx.Pwd.Get(); err == nil {
+			if err = t.config.Pwd.Set(pwd); err != nil {
+				s.ctx.Logger.Error("failed set tree dir", "dir", pwd, "err", err)
+			}
+		}
+	}
+	if t.config.RefreshSideBar != nil {
+		t.config.RefreshSideBar()
+	}
+}
@@ -1,120 +1,571 @@
 package components
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 	"github.com/meteormin/minder/commands"
 )
 
+const (
+	// flushDelay 명령 출력을 모아서 화면에 반영하는 간격
+	flushDelay = 50 * time.Millisecond
+	// defaultScrollback 콘솔에 남겨 두는 줄 수 (TerminalConfig.Scrollback)
+	defaultScrollback = 10000
+)
+
 type Console struct {
-	grid   *widget.TextGrid
+	box    *fyne.Container // 출력 블록: 텍스트는 TextGrid, 표는 widget.Table
 	scroll *container.Scroll
-	buf    strings.Builder
-	mu     sync.Mutex
-	cmd    string
+	buf    commands.Buffer // 자체 잠금이 있어 여러 고루틴에서 써도 된다
+	mu     sync.Mutex      // pending, running, nextID, queue, working
+
+	pending bool // flush 예약됨
+
+	objs  []fyne.CanvasObject // box에 올라간 블록 위젯 (buf 블록과 같은 순서)
+	shown []commands.Block    // objs가 그리고 있는 내용
+
+	search  *consoleSearch
+	selGrid *logGrid // 글자가 선택된 블록
+
+	onDone      func()         // 명령 하나가 끝날 때마다 (프롬프트 갱신 등)
+	onOpen      func(p string) // 표의 경로를 눌렀을 때
+	focusPrompt func()
+
+	logger  *slog.Logger
+	base    context.Context // 창이 닫히면 취소된다
+	running map[int]context.CancelFunc
+	nextID  int
+
+	// 명령은 탭마다 하나씩 차례로 실행한다 (commands.Context를 함께 쓴다)
+	queue   []func()
+	working bool // work 고루틴이 돌고 있음
 }
 
-func (cs *Console) println(line string) {
+// enqueue 실행 중인 명령이 있으면 그것이 끝난 뒤에 차례로 실행한다
+func (cs *Console) enqueue(job func()) {
 	cs.mu.Lock()
-	cs.buf.WriteString(line)
-	cs.buf.WriteByte('\n')
+	cs.queue = append(cs.queue, job)
+	if cs.working {
+		cs.mu.Unlock()
+		return
+	}
+	cs.working = true
 	cs.mu.Unlock()
-	text := cs.buf.String()
-	fyne.Do(func() {
-		cs.grid.SetText(text)
-		// 레이아웃 반영 직후 바닥으로
-		cs.scroll.ScrollToBottom()
-	})
+	go cs.work()
 }
 
-func (cs *Console) render() {
+func (cs *Console) work() {
+	for {
+		cs.mu.Lock()
+		if len(cs.queue) == 0 {
+			cs.working = false
+			cs.mu.Unlock()
+			return
+		}
+		job := cs.queue[0]
+		cs.queue = cs.queue[1:]
+		cs.mu.Unlock()
+		cs.run(job)
+	}
+}
+
+// run 명령 밖(프롬프트 갱신, rc 등)에서 난 panic도 이 탭의 고루틴과 창을 죽이지 않게 한다
+func (cs *Console) run(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			cs.logger.Error("console job panicked", "panic", r, "stack", string(debug.Stack()))
+			cs.buf.EndLine()
+			cs.println(commands.ErrorText(fmt.Sprintf("panic: %v", r)))
+		}
+	}()
+	job()
+}
+
+// begin 실행할 명령의 context. 끝나면 end를 호출해야 한다.
+func (cs *Console) begin() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(cs.base)
+
 	cs.mu.Lock()
-	cs.buf.WriteByte('\n')
+	id := cs.nextID
+	cs.nextID++
+	cs.running[id] = cancel
 	cs.mu.Unlock()
-	text := cs.buf.String()
+
+	return ctx, func() {
+		cs.mu.Lock()
+		delete(cs.running, id)
+		cs.mu.Unlock()
+		cancel()
+	}
+}
+
+// interrupt Ctrl+C: 실행 중인 명령을 취소하고 기다리던 명령은 버린다
+func (cs *Console) interrupt() {
+	cs.mu.Lock()
+	cancels := make([]context.CancelFunc, 0, len(cs.running))
+	for _, cancel := range cs.running {
+		cancels = append(cancels, cancel)
+	}
+	cs.queue = nil
+	cs.mu.Unlock()
+
+	cs.println("^C")
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+func (cs *Console) println(line string) {
+	_, _ = cs.buf.WriteString(line + "\n")
+	cs.refresh()
+}
+
+func (cs *Console) render() {
+	cs.buf.EndLine()
+	cs.refresh()
+}
+
+// finish 명령 블록 마무리: 오류와 꼬리말(종료 코드, 걸린 시간)
+func (cs *Console) finish(err error, start time.Time) {
+	cs.buf.EndLine()
+	if err != nil {
+		_, _ = cs.buf.WriteString(commands.ErrorText(err.Error()) + "\n")
+	}
+	cs.println(commands.StatusText(commands.ExitCode(err), time.Since(start)))
+}
+
+// schedule 실행 중인 명령의 출력: flushDelay 안의 쓰기는 한 번에 반영
+func (cs *Console) schedule() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.pending {
+		return
+	}
+	cs.pending = true
+	time.AfterFunc(flushDelay, func() {
+		cs.mu.Lock()
+		cs.pending = false
+		cs.mu.Unlock()
+		cs.refresh()
+	})
+}
+
+func (cs *Console) refresh() {
 	fyne.Do(func() {
-		cs.grid.SetText(text)
+		// 스냅숏은 UI 스레드에서: 늦게 도착한 예전 내용으로 덮어쓰지 않도록
+		dropped := cs.sync(cs.buf.Blocks())
+		if cs.search.active() {
+			// 검색 중에는 보던 위치를 유지
+			if dropped {
+				cs.search.refind()
+			}
+			return
+		}
 		// 레이아웃 반영 직후 바닥으로
 		cs.scroll.ScrollToBottom()
 	})
 }
 
-func (cs *Console) command() string {
-	return cs.cmd
+// sync 바뀐 블록만 다시 그린다. 블록 ID로 맞춰 보고 스크롤백에서 밀려났거나
+// clear로 지워진 앞 블록은 떼어 낸다. 떼어 낸 것이 있으면 true.
+func (cs *Console) sync(blocks []commands.Block) bool {
+	drop := 0
+	for drop < len(cs.shown) && (len(blocks) == 0 || cs.shown[drop].ID < blocks[0].ID) {
+		if cs.objs[drop] == cs.selGrid {
+			cs.selGrid = nil
+		}
+		drop++
+	}
+	if drop > 0 {
+		cs.objs, cs.shown = cs.objs[drop:], cs.shown[drop:]
+		cs.box.Objects = append([]fyne.CanvasObject(nil), cs.objs...)
+		cs.box.Refresh()
+	}
+
+	for i, bl := range blocks {
+		if i < len(cs.objs) {
+			if bl.Table == nil && bl.Text != cs.shown[i].Text {
+				cs.objs[i].(*logGrid).setText(strings.TrimSuffix(bl.Text, "\n"))
+				cs.shown[i] = bl
+			}
+			continue
+		}
+
+		var obj fyne.CanvasObject
+		if bl.Table != nil {
+			obj = newTableBlock(bl.Table, cs.onOpen)
+		} else {
+			grid := cs.newGrid()
+			grid.setText(strings.TrimSuffix(bl.Text, "\n"))
+			obj = grid
+		}
+		cs.objs = append(cs.objs, obj)
+		cs.shown = append(cs.shown, bl)
+		cs.box.Add(obj)
+	}
+	return drop > 0
+}
+
+// newGrid 텍스트 블록: 드래그한 글자는 클립보드로, 선택은 한 블록에만
+func (cs *Console) newGrid() *logGrid {
+	g := newLogGrid()
+	g.onSelect = func(g *logGrid) {
+		if cs.selGrid != nil && cs.selGrid != g {
+			cs.selGrid.unselect()
+		}
+		cs.selGrid = g
+	}
+	g.onCopy = func(text string) {
+		fyne.CurrentApp().Clipboard().SetContent(text)
+	}
+	g.onTap = cs.focusPrompt
+	return g
+}
+
+// openSearch Ctrl+F
+func (cs *Console) openSearch() {
+	cs.search.bar.Show()
+	if c := fyne.CurrentApp().Driver().CanvasForObject(cs.search.entry); c != nil {
+		c.Focus(cs.search.entry)
+	}
+	cs.search.find(cs.search.entry.Text)
+}
+
+func (cs *Console) closeSearch() {
+	cs.search.clear()
+	cs.search.bar.Hide()
+	cs.scroll.ScrollToBottom()
+	if cs.focusPrompt != nil {
+		cs.focusPrompt()
+	}
+}
+
+// consoleWriter 명령이 쓰는 commands.Output. Console.buf에 쌓고 flushDelay 뒤에 화면에 반영한다.
+type consoleWriter struct {
+	cs *Console
+}
+
+func (w consoleWriter) Write(p []byte) (int, error) {
+	n, err := w.cs.buf.Write(p)
+	w.cs.schedule()
+	return n, err
+}
+
+func (w consoleWriter) WriteString(s string) (int, error) {
+	n, err := w.cs.buf.WriteString(s)
+	w.cs.schedule()
+	return n, err
 }
 
-func (cs *Console) handleSubmitted(c *commands.Context) {
-	cmd := cs.command()
-	cmdErr := commands.Call(c, cmd)
-	if cmdErr != nil {
-		cs.println(cmdErr.Error())
+func (w consoleWriter) WriteByte(ch byte) error {
+	err := w.cs.buf.WriteByte(ch)
+	w.cs.schedule()
+	return err
+}
+
+func (w consoleWriter) WriteTable(t *commands.Table) error {
+	err := w.cs.buf.WriteTable(t)
+	w.cs.schedule()
+	return err
+}
+
+func (w consoleWriter) Len() int {
+	return w.cs.buf.Len()
+}
+
+func (w consoleWriter) Reset() {
+	w.cs.buf.Reset()
+	w.cs.schedule()
+}
+
+func (w consoleWriter) String() string {
+	return w.cs.buf.String()
+}
+
+func (cs *Console) done() {
+	if cs.onDone != nil {
+		cs.onDone()
+	}
+}
+
+func (cs *Console) handleSubmitted(c *commands.Context, cmd string) {
+	defer cs.done()
+	ctx, end := cs.begin()
+	defer end()
+
+	start := time.Now()
+	cs.finish(commands.Call(ctx, c, cmd), start)
+}
+
+// runRc 시작 시 ~/.minderrc 실행 결과를 콘솔에 표시
+func (cs *Console) runRc(c *commands.Context) {
+	defer cs.done()
+	ctx, end := cs.begin()
+	defer end()
+
+	ran, err := commands.RunRc(ctx, c)
+	if err != nil {
+		cs.buf.EndLine()
+		cs.println(commands.ErrorText(err.Error()))
 		return
 	}
-	cs.render()
+	if ran {
+		cs.render()
+	}
 }
 
-type TerminalState struct {
-	Input binding.String
+// promptEntry 선택한 글자가 없을 때 Ctrl+C를 실행 중인 명령 취소로 쓴다. Ctrl+F는 검색, Ctrl+T는 새 탭.
+// ↑/↓로 이 탭에서 입력한 명령을 오간다.
+type promptEntry struct {
+	widget.Entry
+	onInterrupt func()
+	onFind      func()
+	onNewTab    func()
+
+	history []string
+	cursor  int    // history 위치. len(history)면 입력 중인 줄
+	draft   string // 기록을 훑기 전에 입력하던 줄
 }
 
-type TerminalConfig struct {
-	Pwd            binding.String
-	Input          binding.String
-	Logger         *slog.Logger
-	Window         fyne.Window
-	RefreshSideBar func()
+func newPromptEntry(data binding.String) *promptEntry {
+	e := &promptEntry{}
+	e.ExtendBaseWidget(e)
+	e.Bind(data)
+	return e
 }
 
-type Terminal struct {
-	State     TerminalState
-	Container fyne.CanvasObject
+func (e *promptEntry) TypedShortcut(s fyne.Shortcut) {
+	if isInterrupt(s) && e.SelectedText() == "" && e.onInterrupt != nil {
+		e.onInterrupt()
+		return
+	}
+	if isFind(s) && e.onFind != nil {
+		e.onFind()
+		return
+	}
+	if isNewTab(s) && e.onNewTab != nil {
+		e.onNewTab()
+		return
+	}
+	e.Entry.TypedShortcut(s)
 }
 
-func NewTerminal(config TerminalConfig) *Terminal {
-	// 히스토리: TextGrid + 바깥 VScroll (Entry 아님)
-	grid := widget.NewTextGrid()
-	scroll := container.NewVScroll(grid)
+func (e *promptEntry) TypedKey(k *fyne.KeyEvent) {
+	switch k.Name {
+	case fyne.KeyUp:
+		e.recall(-1)
+	case fyne.KeyDown:
+		e.recall(1)
+	default:
+		e.Entry.TypedKey(k)
+	}
+}
+
+// remember 실행한 명령을 기록 (바로 앞과 같으면 한 번만)
+func (e *promptEntry) remember(line string) {
+	if n := len(e.history); n == 0 || e.history[n-1] != line {
+		e.history = append(e.history, line)
+	}
+	e.cursor, e.draft = len(e.history), ""
+}
+
+func (e *promptEntry) recall(d int) {
+	next := e.cursor + d
+	if next < 0 || next > len(e.history) {
+		return
+	}
+	if e.cursor == len(e.history) {
+		e.draft = e.Text
+	}
+	e.cursor = next
+
+	line := e.draft
+	if next < len(e.history) {
+		line = e.history[next]
+	}
+	e.SetText(line)
+	e.CursorColumn = len([]rune(line))
+	e.Refresh()
+}
+
+// isInterrupt Ctrl+C. 리눅스/윈도우에서는 복사 단축키로, macOS에서는 별도 조합으로 들어온다
+func isInterrupt(s fyne.Shortcut) bool {
+	switch sc := s.(type) {
+	case *fyne.ShortcutCopy:
+		return true
+	case *desktop.CustomShortcut:
+		return sc.KeyName == fyne.KeyC && sc.Modifier == fyne.KeyModifierControl
+	}
+	return false
+}
+
+func isNewTab(s fyne.Shortcut) bool {
+	sc, ok := s.(*desktop.CustomShortcut)
+	return ok && sc.KeyName == fyne.KeyT && sc.Modifier == fyne.KeyModifierShortcutDefault
+}
+
+// session 터미널 탭 하나. 작업 디렉터리, 환경 변수, 디렉터리 스택, 콘솔, 입력 기록을 따로 가진다.
+type session struct {
+	ctx     *commands.Context
+	console *Console
+	prompt  *promptEntry
+	link    *widget.Check // 파일 트리와 연결
+	cancel  context.CancelFunc
+	content fyne.CanvasObject
+	item    *container.TabItem
+}
+
+func newSession(t *Terminal, pwd, input binding.String) *session {
+	config := t.config
+
+	// 히스토리: 블록(TextGrid, widget.Table) VBox + 바깥 VScroll (Entry 아님)
+	// 간격 없이 쌓아 나뉜 텍스트 블록이 이어져 보이게
+	box := container.New(layout.NewCustomPaddedVBoxLayout(0))
+	scroll := container.NewVScroll(box)
 
-	console := &Console{grid: grid, scroll: scroll}
-	console.buf.Reset()
+	// 탭을 닫으면 그 탭에서 실행 중인 명령도 취소
+	base, cancel := context.WithCancel(config.Context)
+	console := &Console{box: box, scroll: scroll, logger: config.Logger, base: base, running: map[int]context.CancelFunc{}}
+	switch {
+	case config.Scrollback == 0:
+		console.buf.MaxLines = defaultScrollback
+	case config.Scrollback > 0:
+		console.buf.MaxLines = config.Scrollback
+	}
+	s := &session{console: console, cancel: cancel}
 	ctx := &commands.Context{
-		Pwd:            config.Pwd,
-		ConsoleBuf:     &console.buf,
-		Logger:         config.Logger,
-		Window:         config.Window,
-		RefreshSideBar: config.RefreshSideBar,
+		Pwd:        pwd,
+		Selected:   config.Selected,
+		Env:        commands.NewEnv(os.Environ()),
+		Dirs:       &commands.DirStack{},
+		ConsoleBuf: consoleWriter{cs: console},
+		Logger:     config.Logger,
+		Window:     config.Window,
+		FS:         config.FS,
+		ShowHidden: config.ShowHidden,
+		// 연결된 탭에서만 트리를 따라 옮긴다
+		RefreshSideBar: func() { t.syncTree(s) },
 	}
+	s.ctx = ctx
 
-	// 프롬프트 + 입력
-	promptLabel := widget.NewLabel(">")
-	prompt := widget.NewEntryWithData(config.Input)
+	// 프롬프트 + 입력 (라벨에 현재 디렉터리와 pushd 스택 표시)
+	promptLabel := widget.NewLabel(ctx.Prompt())
+	console.onDone = func() {
+		prompt := ctx.Prompt()
+		title := s.title()
+		fyne.Do(func() {
+			promptLabel.SetText(prompt)
+			if s.item != nil && s.item.Text != title {
+				s.item.Text = title
+				t.tabs.Refresh()
+			}
+		})
+	}
+	console.onOpen = func(p string) {
+		// 표의 경로: 디렉터리면 cd, 파일이면 미리보기
+		console.enqueue(func() {
+			defer console.done()
+			runCtx, end := console.begin()
+			defer end()
+			start := time.Now()
+			console.finish(commands.OpenPath(runCtx, ctx, p), start)
+		})
+	}
+	prompt := newPromptEntry(input)
+	prompt.onInterrupt = console.interrupt
+	prompt.onFind = console.openSearch
+	prompt.onNewTab = t.newTab
+	console.focusPrompt = s.focus
+	console.search = newConsoleSearch(console, console.closeSearch)
 	prompt.SetPlaceHolder("type here and press Enter")
-	prompt.OnSubmitted = func(s string) {
-		if s == "" {
+	prompt.OnSubmitted = func(line string) {
+		if line == "" {
 			return
 		}
+		prompt.remember(line)
 		// 프롬프트와 함께 즉시 출력 (UI 스레드)
-		console.cmd = s
-		console.println("> " + s)
+		console.println("> " + line)
 		prompt.SetText("")
 
-		// 실제 처리는 고루틴에서, UI 갱신은 console.Println 사용
-		go console.handleSubmitted(ctx)
+		// 실제 처리는 고루틴에서 하나씩, 출력은 consoleWriter가 flushDelay마다 반영
+		console.enqueue(func() { console.handleSubmitted(ctx, line) })
 	}
+	s.prompt = prompt
+
+	s.link = widget.NewCheck("tree", func(on bool) {
+		if on {
+			t.setLinked(s)
+		} else if t.isLinked(s) {
+			t.setLinked(nil)
+		}
+	})
 
-	bottom := container.NewBorder(nil, nil, promptLabel, nil, prompt)
-	c := container.NewBorder(nil, bottom, nil, nil, scroll)
+	// ~/.minderrc: 별칭, 변수, 시작 디렉터리 설정. 먼저 입력한 명령보다 앞선다
+	console.enqueue(func() { console.runRc(ctx) })
+
+	// 파일 복사 진행 상황 (느린 복사만, 끝나면 숨긴다)
+	bar := widget.NewProgressBar()
+	barInf := widget.NewProgressBarInfinite()
+	barInf.Stop()
+	barLabel := widget.NewLabel("")
+	progress := container.NewBorder(nil, nil, barLabel, nil, container.NewStack(bar, barInf))
+	progress.Hide()
+	ctx.Progress = func(tr commands.Transfer) {
+		text, frac := tr.String(), tr.Fraction()
+		fyne.Do(func() {
+			if tr.End {
+				barInf.Stop()
+				progress.Hide()
+				return
+			}
+			barLabel.SetText(text)
+			if frac < 0 {
+				bar.Hide()
+				barInf.Show()
+				barInf.Start()
+			} else {
+				barInf.Stop()
+				barInf.Hide()
+				bar.Show()
+				bar.SetValue(frac)
+			}
+			progress.Show()
+		})
+	}
+
+	bottom := container.NewVBox(progress, container.NewBorder(nil, nil, promptLabel, s.link, prompt))
+	s.content = container.NewBorder(console.search.bar, bottom, nil, nil, scroll)
+	return s
+}
+
+// title 탭 제목: 작업 디렉터리 이름
+func (s *session) title() string {
+	pwd, err := s.ctx.Pwd.Get()
+	if err != nil || pwd == "" {
+		return "shell"
+	}
+	if name := filepath.Base(pwd); name != string(filepath.Separator) && name != "." {
+		return name
+	}
+	return pwd
+}
 
-	return &Terminal{
-		State: TerminalState{
-			Input: config.Input,
-		},
-		Container: c,
+func (s *session) focus() {
+	if c := fyne.CurrentApp().Driver().CanvasForObject(s.prompt); c != nil {
+		c.Focus(s.prompt)
 	}
 }
@@ -0,0 +1,261 @@
+package components
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/fsnotify/fsnotify"
+	"github.com/meteormin/minder/vfs"
+)
+
+// watchDelay 이벤트를 모으는 시간 (저장, 압축 해제, 빌드는 이벤트가 몰려서 온다)
+const watchDelay = 250 * time.Millisecond
+
+// dirWatcher 로컬 디렉터리들을 fsnotify로 지켜보고 바뀐 디렉터리를 모아서 알린다.
+// 원격 저장소나 아카이브 안처럼 로컬이 아닌 경로는 지켜보지 않는다.
+type dirWatcher struct {
+	fsys vfs.ReadFS
+	// onChange UI 스레드에서. 디렉터리(add에 준 이름) → 바뀐 항목 이름
+	onChange func(changes map[string][]string)
+	// onLimit UI 스레드에서. 한도(inotify watch 수, 열린 파일 수)에 걸려 못 지키는 디렉터리가 있는지
+	onLimit func(limited bool)
+
+	w *fsnotify.Watcher // 만들지 못했으면 nil
+
+	mu      sync.Mutex
+	dirs    map[string]string   // 로컬 경로 → add에 준 이름
+	lost    map[string]string   // 지워지거나 옮겨져 놓친 디렉터리. 같은 경로가 다시 생기면 다시 지켜본다 (remove 전까지)
+	limited map[string]struct{} // 한도 때문에 못 지키는 로컬 경로 (w가 nil이면 "")
+	shown   bool                // onLimit으로 마지막에 알린 값
+	pending map[string]map[string]struct{}
+	timer   *time.Timer
+}
+
+func newDirWatcher(fsys vfs.ReadFS, onChange func(map[string][]string), onLimit func(bool)) *dirWatcher {
+	dw := &dirWatcher{
+		fsys:     fsys,
+		onChange: onChange,
+		onLimit:  onLimit,
+		dirs:     map[string]string{},
+		lost:     map[string]string{},
+		limited:  map[string]struct{}{},
+		pending:  map[string]map[string]struct{}{},
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		fyne.LogError("failed create watcher", err)
+		if isWatchLimit(err) {
+			dw.limited[""] = struct{}{}
+			dw.reportLimit()
+		}
+		return dw
+	}
+	dw.w = w
+	go dw.loop()
+	return dw
+}
+
+// isWatchLimit inotify의 max_user_watches(ENOSPC), max_user_instances와 kqueue의 열린 파일 수(EMFILE)
+func isWatchLimit(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
+
+// add 디렉터리 name을 지켜본다. 이미 보고 있거나 로컬이 아니면 아무것도 하지 않는다
+func (dw *dirWatcher) add(name string) {
+	lp, ok := vfs.LocalPath(dw.fsys, name)
+	if !ok || dw.w == nil {
+		return
+	}
+	lp = filepath.Clean(lp)
+
+	dw.mu.Lock()
+	defer dw.reportLimit()
+	defer dw.mu.Unlock()
+	if _, ok := dw.dirs[lp]; ok {
+		return
+	}
+	if err := dw.w.Add(lp); err != nil {
+		if isWatchLimit(err) {
+			dw.limited[lp] = struct{}{}
+		} else {
+			fyne.LogError("failed watch "+lp, err)
+		}
+		return
+	}
+	delete(dw.limited, lp)
+	delete(dw.lost, lp)
+	dw.dirs[lp] = name
+}
+
+// remove 더는 지켜보지 않는다
+func (dw *dirWatcher) remove(name string) {
+	lp, ok := vfs.LocalPath(dw.fsys, name)
+	if !ok || dw.w == nil {
+		return
+	}
+	lp = filepath.Clean(lp)
+
+	dw.mu.Lock()
+	defer dw.reportLimit()
+	defer dw.mu.Unlock()
+	if _, ok := dw.dirs[lp]; ok {
+		delete(dw.dirs, lp)
+		_ = dw.w.Remove(lp) // 지워진 디렉터리는 fsnotify가 이미 뺐다
+	}
+	delete(dw.lost, lp)
+	delete(dw.limited, lp)
+}
+
+// close 모두 멈춘다
+func (dw *dirWatcher) close() {
+	if dw.w != nil {
+		_ = dw.w.Close()
+	}
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if dw.timer != nil {
+		dw.timer.Stop()
+	}
+}
+
+// reportLimit 한도 상태가 바뀌었으면 알린다. mu를 놓고 부른다
+func (dw *dirWatcher) reportLimit() {
+	dw.mu.Lock()
+	limited := len(dw.limited) > 0
+	changed := limited != dw.shown
+	dw.shown = limited
+	dw.mu.Unlock()
+	if changed && dw.onLimit != nil {
+		fyne.Do(func() { dw.onLimit(limited) })
+	}
+}
+
+func (dw *dirWatcher) loop() {
+	for {
+		select {
+		case ev, ok := <-dw.w.Events:
+			if !ok {
+				return
+			}
+			dw.handle(ev)
+		case err, ok := <-dw.w.Errors:
+			if !ok {
+				return
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// 큐가 넘쳐 이벤트를 잃었다: 지켜보는 디렉터리를 모두 다시 읽는다
+				dw.mu.Lock()
+				for _, name := range dw.dirs {
+					dw.mark(name, "")
+				}
+				dw.mu.Unlock()
+				continue
+			}
+			fyne.LogError("watch failed", err)
+		}
+	}
+}
+
+func (dw *dirWatcher) handle(ev fsnotify.Event) {
+	if ev.Op == fsnotify.Chmod {
+		// 속성만 바뀐 것 (Spotlight, 백신이 자주 낸다)
+		return
+	}
+	lp := filepath.Clean(ev.Name)
+	dw.mu.Lock()
+	defer dw.reportLimit()
+	defer dw.mu.Unlock()
+	if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+		// 지켜보던 디렉터리 자신이 사라졌다. 열린 채로 남아 있으니 다시 생기길 기다린다
+		if name, ok := dw.dirs[lp]; ok {
+			delete(dw.dirs, lp)
+			dw.lost[lp] = name
+		}
+	}
+	if name, ok := dw.lost[lp]; ok && ev.Has(fsnotify.Create) {
+		dw.readd(lp, name)
+	}
+	if name, ok := dw.dirs[filepath.Dir(lp)]; ok {
+		dw.mark(name, filepath.Base(lp))
+	}
+}
+
+// readd mu를 잡고 부른다. 놓쳤던 디렉터리가 다시 생겼다: 다시 지켜보고 내용을 다시 읽게 한다
+func (dw *dirWatcher) readd(lp, name string) {
+	if fi, err := os.Stat(lp); err != nil || !fi.IsDir() {
+		// 같은 이름의 파일이면 다음 Create를 기다린다
+		return
+	}
+	if err := dw.w.Add(lp); err != nil {
+		if isWatchLimit(err) {
+			delete(dw.lost, lp)
+			dw.limited[lp] = struct{}{}
+		} else {
+			fyne.LogError("failed watch "+lp, err)
+		}
+		return
+	}
+	delete(dw.lost, lp)
+	dw.dirs[lp] = name
+	dw.mark(name, "")
+}
+
+// mark mu를 잡고 부른다. 첫 이벤트에서 watchDelay 뒤에 한꺼번에 알린다
+func (dw *dirWatcher) mark(dir, name string) {
+	names, ok := dw.pending[dir]
+	if !ok {
+		names = map[string]struct{}{}
+		dw.pending[dir] = names
+	}
+	if name != "" {
+		names[name] = struct{}{}
+	}
+	if dw.timer == nil {
+		dw.timer = time.AfterFunc(watchDelay, dw.flush)
+	}
+}
+
+func (dw *dirWatcher) flush() {
+	dw.mu.Lock()
+	changes := make(map[string][]string, len(dw.pending))
+	for dir, names := range dw.pending {
+		list := make([]string, 0, len(names))
+		for n := range names {
+			list = append(list, n)
+		}
+		changes[dir] = list
+	}
+	dw.pending = map[string]map[string]struct{}{}
+	dw.timer = nil
+	dw.mu.Unlock()
+
+	if len(changes) > 0 {
+		fyne.Do(func() { dw.onChange(changes) })
+	}
+}
+
+// newWatchLimitLabel 감시 한도에 걸렸을 때 보이는 표시. 처음엔 숨겨져 있다
+func newWatchLimitLabel() *widget.Label {
+	lbl := widget.NewLabel("watch limit reached: some folders won't update automatically")
+	lbl.Importance = widget.WarningImportance
+	lbl.Wrapping = fyne.TextWrapWord
+	lbl.Hide()
+	return lbl
+}
+
+// showWatchLimit onLimit에 그대로 넘긴다
+func showWatchLimit(lbl *widget.Label) func(bool) {
+	return func(limited bool) {
+		if limited {
+			lbl.Show()
+		} else {
+			lbl.Hide()
+		}
+	}
+}
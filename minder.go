@@ -1,6 +1,7 @@
 package minder
 
 import (
+	"context"
 	"log/slog"
 
 	"fyne.io/fyne/v2"
@@ -17,12 +18,17 @@ type Store struct {
 }
 
 type Context struct {
+	ctx    context.Context // 창이 닫히면 취소 (실행 중인 터미널 명령 중단)
 	store  *Store
 	logger *slog.Logger
 	window fyne.Window
 	layout *Layout
 }
 
+func (c *Context) Context() context.Context {
+	return c.ctx
+}
+
 func (c *Context) Store() *Store {
 	return c.store
 }
@@ -154,7 +160,11 @@ func New(config Config) (*Context, error) {
 		return nil, err
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	w.SetOnClosed(cancel)
+
 	c := &Context{
+		ctx:    ctx,
 		store:  store,
 		window: w,
 		logger: config.Logger,
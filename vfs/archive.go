@@ -0,0 +1,569 @@
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// maxArchiveBuffer 임의 접근(ReaderAt)이 안 되는 FS의 zip은 통째로 받아 둔다.
+// 캐시에 받아 둔 내용을 모두 합친 한도로, 넘으면 받아 둔 다른 아카이브를 캐시에서 뺀다
+var maxArchiveBuffer int64 = 256 << 20
+
+// maxArchiveCache 목록을 기억해 두는 아카이브 수
+const maxArchiveCache = 16
+
+// archiveFormats 확장자 → 형식 (긴 것부터 비교)
+var archiveFormats = []struct{ ext, format string }{
+	{".tar.gz", "tar.gz"},
+	{".tgz", "tar.gz"},
+	{".tar.bz2", "tar.bz2"},
+	{".tbz2", "tar.bz2"},
+	{".tar", "tar"},
+	{".zip", "zip"},
+}
+
+// ArchiveFormat 이름으로 본 아카이브 형식: zip, tar, tar.gz, tar.bz2. 아니면 ""
+func ArchiveFormat(name string) string {
+	lower := strings.ToLower(name)
+	for _, f := range archiveFormats {
+		if strings.HasSuffix(lower, f.ext) {
+			return f.format
+		}
+	}
+	return ""
+}
+
+// IsArchive 이름이 아카이브 확장자인지
+func IsArchive(name string) bool {
+	return ArchiveFormat(name) != ""
+}
+
+// archiveBrowser 아카이브 파일 안을 디렉터리로 보여 주는 FS (Archives, 그것을 품은 Mux)
+type archiveBrowser interface {
+	isArchiveDir(name string) bool
+}
+
+// IsArchiveDir name이 fsys가 안을 보여 주는 아카이브 파일인지 (트리에서 브랜치로)
+func IsArchiveDir(fsys ReadFS, name string) bool {
+	b, ok := fsys.(archiveBrowser)
+	return ok && b.isArchiveDir(name)
+}
+
+// CleanEntryName 아카이브 항목 이름을 "a/b" 꼴로. 절대 경로나 ".."로 밖을 가리키면 false
+func CleanEntryName(name string) (string, bool) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") || filepath.VolumeName(name) != "" {
+		return "", false
+	}
+	for _, el := range strings.Split(name, "/") {
+		if el == ".." {
+			return "", false
+		}
+	}
+	p := path.Clean(name)
+	if p == "." {
+		return "", true
+	}
+	return p, true
+}
+
+// Archives base의 zip, tar(.gz, .bz2) 파일 안을 읽기 전용 디렉터리로 보여 준다.
+// 아카이브 파일 자신은 그대로 파일이다 (Stat, cp). ReadDir만 최상위 항목을 돌려준다.
+// 안쪽 경로는 /dir/a.zip/inner/file 꼴이고 쓰기는 EROFS로 실패한다.
+type Archives struct {
+	base FS
+	*archiveCache
+}
+
+// archiveCache WithContext로 만든 Archives들이 함께 쓰는 목록
+type archiveCache struct {
+	mu       sync.Mutex
+	cache    map[string]*archive
+	buffered int64 // 캐시에 받아 둔 zip 내용(archive.data)의 합
+}
+
+// drop a.mu를 잡고 부른다
+func (a *archiveCache) drop(arc string) {
+	if ar, ok := a.cache[arc]; ok {
+		a.buffered -= int64(len(ar.data))
+		delete(a.cache, arc)
+	}
+}
+
+// makeRoom a.mu를 잡고 부른다. n바이트를 더 받아도 maxArchiveBuffer를 넘지 않게 받아 둔 아카이브를 뺀다
+func (a *archiveCache) makeRoom(n int64) {
+	for k, ar := range a.cache {
+		if a.buffered+n <= maxArchiveBuffer {
+			return
+		}
+		if ar.data != nil {
+			a.drop(k)
+		}
+	}
+}
+
+func NewArchives(base FS) *Archives {
+	return &Archives{base: base, archiveCache: &archiveCache{cache: map[string]*archive{}}}
+}
+
+func (a *Archives) withContext(ctx context.Context) FS {
+	return &Archives{base: WithContext(ctx, a.base), archiveCache: a.archiveCache}
+}
+
+// archive 한 아카이브의 항목 목록. 크기와 수정 시각이 바뀌면 다시 읽는다.
+type archive struct {
+	format  string
+	size    int64
+	modTime time.Time
+	entries map[string]*archiveEntry // "a/b", 루트는 ""
+	data    []byte                   // ReaderAt가 없는 FS의 zip 내용
+}
+
+type archiveEntry struct {
+	info     memInfo
+	index    int // zip: File 순번, tar: 헤더 순번
+	children []fs.DirEntry
+}
+
+// Close base가 닫을 수 있는 FS면 닫는다 (Mux.Unmount)
+func (a *Archives) Close() error {
+	if c, ok := a.base.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (a *Archives) isArchiveDir(name string) bool {
+	if !IsArchive(name) {
+		return false
+	}
+	fi, err := a.base.Stat(name)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// localPath 아카이브 파일과 그 안은 디렉터리로 지켜볼 수 없다
+func (a *Archives) localPath(name string) (string, bool) {
+	if _, _, ok := a.split(name); ok || a.isArchiveDir(name) {
+		return "", false
+	}
+	return LocalPath(a.base, name)
+}
+
+// split name이 아카이브 안을 가리키면 아카이브 경로와 안쪽 경로("a/b")
+func (a *Archives) split(name string) (arc, inner string, ok bool) {
+	clean := filepath.Clean(name)
+	sep := string(filepath.Separator)
+	for i := 0; i < len(clean); {
+		j := strings.Index(clean[i+1:], sep)
+		if j < 0 {
+			return "", "", false
+		}
+		i += j + 1
+		if prefix := clean[:i]; a.isArchiveDir(prefix) {
+			return prefix, filepath.ToSlash(clean[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func readOnly(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: syscall.EROFS}
+}
+
+// load 항목 목록 (캐시)
+func (a *Archives) load(arc string) (*archive, error) {
+	fi, err := a.base.Stat(arc)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ar, ok := a.cache[arc]; ok && ar.size == fi.Size() && ar.modTime.Equal(fi.ModTime()) {
+		return ar, nil
+	}
+
+	ar := &archive{
+		format:  ArchiveFormat(arc),
+		size:    fi.Size(),
+		modTime: fi.ModTime(),
+		entries: map[string]*archiveEntry{"": {info: memInfo{name: filepath.Base(arc), mode: fs.ModeDir | 0o755, modTime: fi.ModTime()}}},
+	}
+	if ar.format == "zip" {
+		err = a.indexZip(arc, ar)
+	} else {
+		err = a.indexTar(arc, ar)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", arc, err)
+	}
+	for p, e := range ar.entries {
+		if p == "" {
+			continue
+		}
+		parent := ar.entries[dirOf(p)]
+		parent.children = append(parent.children, fs.FileInfoToDirEntry(e.info))
+	}
+	for _, e := range ar.entries {
+		sortEntries(e.children)
+	}
+
+	a.drop(arc)
+	if len(a.cache) >= maxArchiveCache {
+		for k := range a.cache {
+			a.drop(k)
+			break
+		}
+	}
+	a.cache[arc] = ar
+	a.buffered += int64(len(ar.data))
+	return ar, nil
+}
+
+func dirOf(p string) string {
+	if d := path.Dir(p); d != "." {
+		return d
+	}
+	return ""
+}
+
+// add 항목과 (목록에 없는) 상위 디렉터리들
+func (ar *archive) add(name string, index int, size int64, mode fs.FileMode, modTime time.Time) {
+	p, ok := CleanEntryName(name)
+	if !ok || p == "" {
+		return
+	}
+	for d := dirOf(p); d != ""; d = dirOf(d) {
+		if _, ok := ar.entries[d]; ok {
+			break
+		}
+		ar.entries[d] = &archiveEntry{info: memInfo{name: path.Base(d), mode: fs.ModeDir | 0o755, modTime: ar.modTime}}
+	}
+	if mode.IsDir() {
+		size, mode = 0, fs.ModeDir|mode.Perm()
+	} else {
+		mode = mode.Perm()
+	}
+	ar.entries[p] = &archiveEntry{info: memInfo{name: path.Base(p), size: size, mode: mode, modTime: modTime}, index: index}
+}
+
+// openZip 닫을 것(아카이브 파일)과 함께. ar.data는 load가 채운 뒤로 바뀌지 않으므로 읽기만 한다
+func (a *Archives) openZip(arc string, ar *archive) (*zip.Reader, io.Closer, error) {
+	if ar.data != nil {
+		zr, err := newZipReader(bytes.NewReader(ar.data), int64(len(ar.data)))
+		return zr, io.NopCloser(nil), err
+	}
+	f, err := a.base.Open(arc)
+	if err != nil {
+		return nil, nil, err
+	}
+	ra, ok := f.(io.ReaderAt)
+	if !ok {
+		// load가 받아 두지 않았다 (목록을 읽은 뒤에 마운트가 바뀌었다)
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("%s: random access not supported", arc)
+	}
+	zr, err := newZipReader(ra, ar.size)
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	return zr, f, nil
+}
+
+// newZipReader 밖을 가리키는 항목이 있어도 연다 (그런 항목은 목록에서 뺀다)
+func newZipReader(r io.ReaderAt, size int64) (*zip.Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if errors.Is(err, zip.ErrInsecurePath) {
+		err = nil
+	}
+	return zr, err
+}
+
+// indexZip load가 a.mu를 잡고 부른다. 임의 접근(ReaderAt)이 안 되는 FS면 여기서 ar.data를 채운다
+func (a *Archives) indexZip(arc string, ar *archive) error {
+	f, err := a.base.Open(arc)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	ra, ok := f.(io.ReaderAt)
+	size := ar.size
+	if !ok {
+		// 원격(WebDAV, S3)은 통째로 받는다
+		if ar.size > maxArchiveBuffer {
+			return fmt.Errorf("archive too large to browse here (%d bytes)", ar.size)
+		}
+		// 바뀌어서 다시 읽는 것이면 예전 내용부터 놓는다
+		a.drop(arc)
+		a.makeRoom(ar.size)
+		if ar.data, err = io.ReadAll(f); err != nil {
+			return err
+		}
+		ra, size = bytes.NewReader(ar.data), int64(len(ar.data))
+	}
+	zr, err := newZipReader(ra, size)
+	if err != nil {
+		return err
+	}
+	for i, zf := range zr.File {
+		mode := zf.Mode()
+		if strings.HasSuffix(zf.Name, "/") {
+			mode |= fs.ModeDir
+		}
+		if !mode.IsDir() && !mode.IsRegular() {
+			continue
+		}
+		ar.add(zf.Name, i, int64(zf.UncompressedSize64), mode, zf.Modified)
+	}
+	return nil
+}
+
+// OpenTar format(tar, tar.gz, tar.bz2)에 맞게 압축을 풀어 읽는다
+func OpenTar(r io.Reader, format string) (*tar.Reader, error) {
+	br := bufio.NewReader(r)
+	switch format {
+	case "tar.gz":
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(gz), nil
+	case "tar.bz2":
+		return tar.NewReader(bzip2.NewReader(br)), nil
+	}
+	return tar.NewReader(br), nil
+}
+
+func (a *Archives) openTar(arc, format string) (*tar.Reader, io.Closer, error) {
+	f, err := a.base.Open(arc)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr, err := OpenTar(f, format)
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	return tr, f, nil
+}
+
+func (a *Archives) indexTar(arc string, ar *archive) error {
+	tr, c, err := a.openTar(arc, ar.format)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+	for i := 0; ; i++ {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil && !errors.Is(err, tar.ErrInsecurePath) {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeReg, tar.TypeDir:
+			ar.add(hdr.Name, i, hdr.Size, hdr.FileInfo().Mode(), hdr.ModTime)
+		}
+	}
+}
+
+// entry 안쪽 경로의 항목
+func (a *Archives) entry(op, name, arc, inner string) (*archive, *archiveEntry, error) {
+	ar, err := a.load(arc)
+	if err != nil {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	p, _ := CleanEntryName(inner)
+	e, ok := ar.entries[p]
+	if !ok {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return ar, e, nil
+}
+
+func (a *Archives) Stat(name string) (fs.FileInfo, error) {
+	arc, inner, ok := a.split(name)
+	if !ok {
+		return a.base.Stat(name)
+	}
+	_, e, err := a.entry("stat", name, arc, inner)
+	if err != nil {
+		return nil, err
+	}
+	return e.info, nil
+}
+
+func (a *Archives) Lstat(name string) (fs.FileInfo, error) {
+	if _, _, ok := a.split(name); ok {
+		return a.Stat(name)
+	}
+	return a.base.Lstat(name)
+}
+
+func (a *Archives) ReadDir(name string) ([]fs.DirEntry, error) {
+	arc, inner, ok := a.split(name)
+	if !ok {
+		if !a.isArchiveDir(name) {
+			return a.base.ReadDir(name)
+		}
+		arc, inner = filepath.Clean(name), ""
+	}
+	_, e, err := a.entry("readdir", name, arc, inner)
+	if err != nil {
+		return nil, err
+	}
+	if !e.info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: syscall.ENOTDIR}
+	}
+	return append([]fs.DirEntry(nil), e.children...), nil
+}
+
+func (a *Archives) Open(name string) (File, error) {
+	arc, inner, ok := a.split(name)
+	if !ok {
+		return a.base.Open(name)
+	}
+	ar, e, err := a.entry("open", name, arc, inner)
+	if err != nil {
+		return nil, err
+	}
+	if e.info.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	}
+
+	f := &archiveFile{name: name, info: e.info}
+	if ar.format == "zip" {
+		zr, c, err := a.openZip(arc, ar)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		rc, err := zr.File[e.index].Open()
+		if err != nil {
+			_ = c.Close()
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		f.r, f.closers = rc, []io.Closer{rc, c}
+		return f, nil
+	}
+
+	// tar는 처음부터 그 항목까지 건너뛴다
+	tr, c, err := a.openTar(arc, ar.format)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	for i := 0; i <= e.index; i++ {
+		if _, err = tr.Next(); err != nil && !errors.Is(err, tar.ErrInsecurePath) {
+			_ = c.Close()
+			if errors.Is(err, io.EOF) {
+				err = fs.ErrNotExist
+			}
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+	f.r, f.closers = tr, []io.Closer{c}
+	return f, nil
+}
+
+func (a *Archives) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	if _, _, ok := a.split(name); !ok {
+		return a.base.OpenFile(name, flag, perm)
+	}
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		return nil, readOnly("open", name)
+	}
+	return a.Open(name)
+}
+
+func (a *Archives) Mkdir(name string, perm fs.FileMode) error {
+	if _, _, ok := a.split(name); ok {
+		return readOnly("mkdir", name)
+	}
+	return a.base.Mkdir(name, perm)
+}
+
+func (a *Archives) MkdirAll(name string, perm fs.FileMode) error {
+	if _, _, ok := a.split(name); ok {
+		return readOnly("mkdir", name)
+	}
+	return a.base.MkdirAll(name, perm)
+}
+
+func (a *Archives) Remove(name string) error {
+	if _, _, ok := a.split(name); ok {
+		return readOnly("remove", name)
+	}
+	return a.base.Remove(name)
+}
+
+func (a *Archives) RemoveAll(name string) error {
+	if _, _, ok := a.split(name); ok {
+		return readOnly("removeall", name)
+	}
+	return a.base.RemoveAll(name)
+}
+
+func (a *Archives) Rename(oldName, newName string) error {
+	_, _, inOld := a.split(oldName)
+	_, _, inNew := a.split(newName)
+	if inOld || inNew {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: syscall.EROFS}
+	}
+	return a.base.Rename(oldName, newName)
+}
+
+func (a *Archives) Chmod(name string, mode fs.FileMode) error {
+	if _, _, ok := a.split(name); ok {
+		return readOnly("chmod", name)
+	}
+	return a.base.Chmod(name, mode)
+}
+
+func (a *Archives) Chtimes(name string, atime, mtime time.Time) error {
+	if _, _, ok := a.split(name); ok {
+		return readOnly("chtimes", name)
+	}
+	return a.base.Chtimes(name, atime, mtime)
+}
+
+// archiveFile 아카이브 항목 하나를 읽는다. 닫으면 아카이브 파일도 닫는다.
+type archiveFile struct {
+	name    string
+	info    fs.FileInfo
+	r       io.Reader
+	closers []io.Closer
+}
+
+func (f *archiveFile) Read(p []byte) (int, error) { return f.r.Read(p) }
+
+func (f *archiveFile) Write([]byte) (int, error) {
+	return 0, readOnly("write", f.name)
+}
+
+func (f *archiveFile) Close() error {
+	var err error
+	for _, c := range f.closers {
+		if cErr := c.Close(); err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func (f *archiveFile) Stat() (fs.FileInfo, error) { return f.info, nil }
@@ -0,0 +1,95 @@
+package vfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func writeTestZip(t *testing.T, fsys FS, name string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for n, body := range files {
+		w, err := zw.Create(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(fsys, name, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Mem 파일은 ReaderAt가 없어서 zip을 통째로 받아 둔다. 동시에 열어도 안전해야 한다 (-race)
+func TestArchivesZipBufferedConcurrent(t *testing.T) {
+	mem := NewMem()
+	files := map[string]string{}
+	for i := range 8 {
+		files[fmt.Sprintf("d/f%d.txt", i)] = fmt.Sprintf("body %d", i)
+	}
+	writeTestZip(t, mem, "/a.zip", files)
+
+	a := NewArchives(mem)
+	var wg sync.WaitGroup
+	for n, want := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, err := ReadFile(a, "/a.zip/"+n)
+			if err != nil || string(b) != want {
+				t.Errorf("ReadFile(%s) = %q, %v; want %q", n, b, err, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ents, err := a.ReadDir("/a.zip/d")
+	if err != nil || len(ents) != len(files) {
+		t.Errorf("ReadDir = %d entries, %v; want %d", len(ents), err, len(files))
+	}
+	if !IsArchiveDir(a, "/a.zip") {
+		t.Error("a.zip is not browsable")
+	}
+}
+
+// 받아 둔 zip 내용의 합은 maxArchiveBuffer를 넘지 않는다. 빠진 아카이브는 다시 받는다
+func TestArchivesBufferLimit(t *testing.T) {
+	mem := NewMem()
+	names := []string{"/a.zip", "/b.zip", "/c.zip"}
+	for _, n := range names {
+		writeTestZip(t, mem, n, map[string]string{"f.txt": strings.Repeat(n, 100)})
+	}
+	fi, err := mem.Stat("/a.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := maxArchiveBuffer
+	maxArchiveBuffer = 2*fi.Size() + fi.Size()/2 // 둘까지
+	defer func() { maxArchiveBuffer = prev }()
+
+	a := NewArchives(mem)
+	for range 2 {
+		for _, n := range names {
+			b, err := ReadFile(a, n+"/f.txt")
+			if err != nil || string(b) != strings.Repeat(n, 100) {
+				t.Fatalf("ReadFile(%s) = %q, %v", n, b, err)
+			}
+			a.mu.Lock()
+			buffered, cached := a.buffered, len(a.cache)
+			a.mu.Unlock()
+			if buffered > maxArchiveBuffer || cached > 2 {
+				t.Fatalf("after %s: buffered %d (max %d), %d cached", n, buffered, maxArchiveBuffer, cached)
+			}
+		}
+	}
+}
@@ -0,0 +1,419 @@
+package vfs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Mem 메모리 파일 시스템. 테스트에서 cp, mv, rm을 빠르고 결정적으로 돌리기 위한 것.
+// 심볼릭 링크는 없다 (Lstat == Stat). 여러 고루틴에서 써도 된다.
+type Mem struct {
+	mu    sync.RWMutex
+	nodes map[string]*memNode // 정리된 절대 경로 → 파일/디렉터리
+	root  string
+
+	// Now 새로 만들거나 쓸 때의 수정 시각. nil이면 time.Now
+	Now func() time.Time
+}
+
+type memNode struct {
+	mode    fs.FileMode // 디렉터리는 fs.ModeDir 포함
+	data    []byte
+	modTime time.Time
+}
+
+// NewMem 루트 디렉터리만 있는 빈 파일 시스템
+func NewMem() *Mem {
+	root := string(filepath.Separator)
+	if vol := filepath.VolumeName(os.TempDir()); vol != "" {
+		root = vol + root
+	}
+	m := &Mem{nodes: map[string]*memNode{}, root: root}
+	m.nodes[root] = &memNode{mode: fs.ModeDir | 0o755, modTime: m.now()}
+	return m
+}
+
+func (m *Mem) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// clean 절대 경로만 받는다
+func (m *Mem) clean(op, name string) (string, error) {
+	if !filepath.IsAbs(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Clean(name), nil
+}
+
+func (m *Mem) children(dir string) []string {
+	var out []string
+	for p := range m.nodes {
+		if p != dir && filepath.Dir(p) == dir {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// parentDir name을 만들 수 있는지: 부모가 있고 디렉터리여야 한다
+func (m *Mem) parentDir(op, name string) error {
+	parent, ok := m.nodes[filepath.Dir(name)]
+	switch {
+	case !ok:
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	case !parent.mode.IsDir():
+		return &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+func (m *Mem) Stat(name string) (fs.FileInfo, error) {
+	p, err := m.clean("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n, ok := m.nodes[p]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return n.info(p), nil
+}
+
+func (m *Mem) Lstat(name string) (fs.FileInfo, error) {
+	return m.Stat(name)
+}
+
+func (m *Mem) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := m.clean("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n, ok := m.nodes[p]
+	switch {
+	case !ok:
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	case !n.mode.IsDir():
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	var ents []fs.DirEntry
+	for _, c := range m.children(p) {
+		ents = append(ents, fs.FileInfoToDirEntry(m.nodes[c].info(c)))
+	}
+	sortEntries(ents)
+	return ents, nil
+}
+
+func (m *Mem) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (m *Mem) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	p, err := m.clean("open", name)
+	if err != nil {
+		return nil, err
+	}
+	write := flag&(os.O_WRONLY|os.O_RDWR) != 0
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nodes[p]
+	switch {
+	case ok && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case ok && n.mode.IsDir() && write:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	case !ok && flag&os.O_CREATE == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case !ok:
+		if err := m.parentDir("open", p); err != nil {
+			return nil, err
+		}
+		n = &memNode{mode: perm.Perm(), modTime: m.now()}
+		m.nodes[p] = n
+	}
+
+	f := &memFile{m: m, name: p, node: n, write: write, read: !write || flag&os.O_RDWR != 0}
+	if write && flag&os.O_TRUNC != 0 {
+		n.data = nil
+		n.modTime = m.now()
+	}
+	if flag&os.O_APPEND != 0 {
+		f.append = true
+	}
+	return f, nil
+}
+
+func (m *Mem) Mkdir(name string, perm fs.FileMode) error {
+	p, err := m.clean("mkdir", name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.nodes[p]; ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if err := m.parentDir("mkdir", p); err != nil {
+		return err
+	}
+	m.nodes[p] = &memNode{mode: fs.ModeDir | perm.Perm(), modTime: m.now()}
+	return nil
+}
+
+func (m *Mem) MkdirAll(name string, perm fs.FileMode) error {
+	p, err := m.clean("mkdir", name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 없는 조상부터 차례로
+	var missing []string
+	for cur := p; ; cur = filepath.Dir(cur) {
+		if n, ok := m.nodes[cur]; ok {
+			if !n.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: cur, Err: syscall.ENOTDIR}
+			}
+			break
+		}
+		missing = append(missing, cur)
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		m.nodes[missing[i]] = &memNode{mode: fs.ModeDir | perm.Perm(), modTime: m.now()}
+	}
+	return nil
+}
+
+func (m *Mem) Remove(name string) error {
+	p, err := m.clean("remove", name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nodes[p]
+	switch {
+	case !ok:
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	case p == m.root:
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+	case n.mode.IsDir() && len(m.children(p)) > 0:
+		return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	delete(m.nodes, p)
+	return nil
+}
+
+func (m *Mem) RemoveAll(name string) error {
+	p, err := m.clean("removeall", name)
+	if err != nil {
+		return err
+	}
+	if p == m.root {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrPermission}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k := range m.nodes {
+		if k == p || isUnder(k, p) {
+			delete(m.nodes, k)
+		}
+	}
+	return nil
+}
+
+func (m *Mem) Rename(oldName, newName string) error {
+	linkErr := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: err}
+	}
+	src, err := m.clean("rename", oldName)
+	if err != nil {
+		return linkErr(fs.ErrInvalid)
+	}
+	dst, err := m.clean("rename", newName)
+	if err != nil {
+		return linkErr(fs.ErrInvalid)
+	}
+	if src == dst {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nodes[src]
+	if !ok {
+		return linkErr(fs.ErrNotExist)
+	}
+	if n.mode.IsDir() && isUnder(dst, src) {
+		return linkErr(fs.ErrInvalid)
+	}
+	if err := m.parentDir("rename", dst); err != nil {
+		return linkErr(fs.ErrNotExist)
+	}
+	if old, ok := m.nodes[dst]; ok {
+		// os.Rename처럼: 파일은 덮어쓰고, 디렉터리는 빈 디렉터리만 바꿀 수 있다
+		switch {
+		case old.mode.IsDir() && !n.mode.IsDir():
+			return linkErr(syscall.EISDIR)
+		case !old.mode.IsDir() && n.mode.IsDir():
+			return linkErr(syscall.ENOTDIR)
+		case old.mode.IsDir() && len(m.children(dst)) > 0:
+			return linkErr(syscall.ENOTEMPTY)
+		}
+	}
+
+	moved := map[string]*memNode{dst: n}
+	for k, v := range m.nodes {
+		if isUnder(k, src) {
+			moved[dst+k[len(src):]] = v
+			delete(m.nodes, k)
+		}
+	}
+	delete(m.nodes, src)
+	for k, v := range moved {
+		m.nodes[k] = v
+	}
+	return nil
+}
+
+func (m *Mem) Chmod(name string, mode fs.FileMode) error {
+	p, err := m.clean("chmod", name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nodes[p]
+	if !ok {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+	}
+	n.mode = n.mode.Type() | mode.Perm()
+	return nil
+}
+
+// Chtimes 접근 시각은 보관하지 않는다
+func (m *Mem) Chtimes(name string, _, mtime time.Time) error {
+	p, err := m.clean("chtimes", name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nodes[p]
+	if !ok {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrNotExist}
+	}
+	if !mtime.IsZero() {
+		n.modTime = mtime
+	}
+	return nil
+}
+
+// isUnder p가 dir 안에 있는지 (dir 자신은 아님)
+func isUnder(p, dir string) bool {
+	if !strings.HasSuffix(dir, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	}
+	return strings.HasPrefix(p, dir)
+}
+
+func (n *memNode) info(p string) fs.FileInfo {
+	name := filepath.Base(p)
+	return memInfo{name: name, size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}
+}
+
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+// memFile 열린 파일. 읽기/쓰기 위치를 따로 갖고 node.data를 직접 읽고 쓴다.
+type memFile struct {
+	m      *Mem
+	name   string
+	node   *memNode
+	pos    int
+	read   bool
+	write  bool
+	append bool
+	closed bool
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	f.m.mu.RLock()
+	defer f.m.mu.RUnlock()
+	switch {
+	case f.closed:
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	case !f.read:
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrPermission}
+	case f.node.mode.IsDir():
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: syscall.EISDIR}
+	case f.pos >= len(f.node.data):
+		return 0, io.EOF
+	}
+	n := copy(p, f.node.data[f.pos:])
+	f.pos += n
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	switch {
+	case f.closed:
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrClosed}
+	case !f.write:
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+	}
+	if f.append {
+		f.pos = len(f.node.data)
+	}
+	if end := f.pos + len(p); end > len(f.node.data) {
+		f.node.data = append(f.node.data, make([]byte, end-len(f.node.data))...)
+	}
+	copy(f.node.data[f.pos:], p)
+	f.pos += len(p)
+	f.node.modTime = f.m.now()
+	return len(p), nil
+}
+
+func (f *memFile) Close() error {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	f.m.mu.RLock()
+	defer f.m.mu.RUnlock()
+	return f.node.info(f.name), nil
+}
@@ -0,0 +1,291 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// MountPrefix 원격 저장소를 붙이는 기본 위치: /@name
+const MountPrefix = "@"
+
+// MountPoint 이름으로 만든 기본 마운트 지점
+func MountPoint(name string) string {
+	return string(filepath.Separator) + MountPrefix + name
+}
+
+// Mux 마운트 지점(절대 경로)마다 다른 FS를 붙인다. 마운트 밖은 기본 FS.
+// 마운트된 FS는 마운트 지점을 루트("/")로 보는 경로를 받는다.
+// 서로 다른 FS 사이의 Rename은 EXDEV로 실패하므로 mv는 복사 후 삭제로 넘어간다.
+type Mux struct {
+	*muxTable
+	ctx context.Context // WithContext로 만든 것만. 마운트된 FS에 넘긴다
+}
+
+// muxTable WithContext로 만든 Mux들이 함께 쓰는 마운트 목록
+type muxTable struct {
+	base FS
+
+	mu     sync.RWMutex
+	mounts map[string]FS
+	notify []func()
+}
+
+// Mount 마운트 지점과 FS
+type Mount struct {
+	Dir string
+	FS  FS
+}
+
+func NewMux(base FS) *Mux {
+	return &Mux{muxTable: &muxTable{base: base, mounts: map[string]FS{}}}
+}
+
+func (m *Mux) withContext(ctx context.Context) FS {
+	return &Mux{muxTable: m.muxTable, ctx: ctx}
+}
+
+// Mount dir에 fsys를 붙인다
+func (m *Mux) Mount(dir string, fsys FS) error {
+	if !filepath.IsAbs(dir) {
+		return &fs.PathError{Op: "mount", Path: dir, Err: fs.ErrInvalid}
+	}
+	dir = filepath.Clean(dir)
+	if filepath.Dir(dir) == dir {
+		return &fs.PathError{Op: "mount", Path: dir, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	if _, ok := m.mounts[dir]; ok {
+		m.mu.Unlock()
+		return &fs.PathError{Op: "mount", Path: dir, Err: fs.ErrExist}
+	}
+	m.mounts[dir] = fsys
+	m.mu.Unlock()
+
+	m.changed()
+	return nil
+}
+
+// Unmount 떼어 낸다. FS가 io.Closer면 닫는다 (연결 정리).
+func (m *Mux) Unmount(dir string) error {
+	dir = filepath.Clean(dir)
+	m.mu.Lock()
+	fsys, ok := m.mounts[dir]
+	delete(m.mounts, dir)
+	m.mu.Unlock()
+	if !ok {
+		return &fs.PathError{Op: "unmount", Path: dir, Err: fs.ErrNotExist}
+	}
+
+	m.changed()
+	if c, ok := fsys.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Mounts 마운트 지점 순
+func (m *Mux) Mounts() []Mount {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]Mount, 0, len(m.mounts))
+	for dir, fsys := range m.mounts {
+		out = append(out, Mount{Dir: dir, FS: fsys})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Dir < out[j].Dir })
+	return out
+}
+
+// Notify 마운트가 바뀔 때마다 f. 마운트한 고루틴에서 불린다.
+func (m *Mux) Notify(f func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notify = append(m.notify, f)
+}
+
+func (m *Mux) changed() {
+	m.mu.RLock()
+	fns := append([]func(){}, m.notify...)
+	m.mu.RUnlock()
+	for _, f := range fns {
+		f()
+	}
+}
+
+// resolve name이 속한 FS와 그 안의 경로. 마운트 밖이면 dir == ""
+func (m *Mux) resolve(name string) (fsys FS, inner, dir string) {
+	fsys, inner, dir = m.lookup(name)
+	return WithContext(m.ctx, fsys), inner, dir
+}
+
+func (m *Mux) lookup(name string) (fsys FS, inner, dir string) {
+	if !filepath.IsAbs(name) {
+		return m.base, name, ""
+	}
+	p := filepath.Clean(name)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for d, f := range m.mounts {
+		// 가장 깊은 마운트 지점
+		if (p == d || isUnder(p, d)) && len(d) > len(dir) {
+			fsys, dir = f, d
+		}
+	}
+	if fsys == nil {
+		return m.base, name, ""
+	}
+	if inner = p[len(dir):]; inner == "" {
+		inner = string(filepath.Separator)
+	}
+	return fsys, inner, dir
+}
+
+// isMountPoint 마운트 지점 자신은 지우거나 옮길 수 없다
+func (m *Mux) isMountPoint(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.mounts[filepath.Clean(name)]
+	return ok
+}
+
+// outer 오류 속 경로를 바깥(마운트 지점 포함) 경로로
+func outer(err error, dir string) error {
+	if err == nil || dir == "" {
+		return err
+	}
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		return &fs.PathError{Op: pe.Op, Path: filepath.Join(dir, pe.Path), Err: pe.Err}
+	}
+	var le *os.LinkError
+	if errors.As(err, &le) {
+		return &os.LinkError{Op: le.Op, Old: filepath.Join(dir, le.Old), New: filepath.Join(dir, le.New), Err: le.Err}
+	}
+	return err
+}
+
+func (m *Mux) isArchiveDir(name string) bool {
+	fsys, p, _ := m.resolve(name)
+	return IsArchiveDir(fsys, p)
+}
+
+func (m *Mux) localPath(name string) (string, bool) {
+	fsys, p, _ := m.resolve(name)
+	return LocalPath(fsys, p)
+}
+
+func (m *Mux) Stat(name string) (fs.FileInfo, error) {
+	fsys, p, dir := m.resolve(name)
+	fi, err := fsys.Stat(p)
+	return fi, outer(err, dir)
+}
+
+func (m *Mux) Lstat(name string) (fs.FileInfo, error) {
+	fsys, p, dir := m.resolve(name)
+	fi, err := fsys.Lstat(p)
+	return fi, outer(err, dir)
+}
+
+// ReadDir 바로 아래의 마운트 지점도 디렉터리로 보여 준다
+func (m *Mux) ReadDir(name string) ([]fs.DirEntry, error) {
+	fsys, p, dir := m.resolve(name)
+	ents, err := fsys.ReadDir(p)
+	if err != nil {
+		return nil, outer(err, dir)
+	}
+
+	parent := filepath.Clean(name)
+	var extra []fs.DirEntry
+	for _, mt := range m.Mounts() {
+		if filepath.Dir(mt.Dir) != parent {
+			continue
+		}
+		base := filepath.Base(mt.Dir)
+		if !containsName(ents, base) {
+			extra = append(extra, fs.FileInfoToDirEntry(memInfo{name: base, mode: fs.ModeDir | 0o755}))
+		}
+	}
+	if len(extra) > 0 {
+		ents = append(ents, extra...)
+		sortEntries(ents)
+	}
+	return ents, nil
+}
+
+func containsName(ents []fs.DirEntry, name string) bool {
+	for _, e := range ents {
+		if e.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Mux) Open(name string) (File, error) {
+	fsys, p, dir := m.resolve(name)
+	f, err := fsys.Open(p)
+	return f, outer(err, dir)
+}
+
+func (m *Mux) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	fsys, p, dir := m.resolve(name)
+	f, err := fsys.OpenFile(p, flag, perm)
+	return f, outer(err, dir)
+}
+
+func (m *Mux) Mkdir(name string, perm fs.FileMode) error {
+	fsys, p, dir := m.resolve(name)
+	return outer(fsys.Mkdir(p, perm), dir)
+}
+
+func (m *Mux) MkdirAll(name string, perm fs.FileMode) error {
+	fsys, p, dir := m.resolve(name)
+	return outer(fsys.MkdirAll(p, perm), dir)
+}
+
+func (m *Mux) Remove(name string) error {
+	if m.isMountPoint(name) {
+		return &fs.PathError{Op: "remove", Path: name, Err: syscall.EBUSY}
+	}
+	fsys, p, dir := m.resolve(name)
+	return outer(fsys.Remove(p), dir)
+}
+
+func (m *Mux) RemoveAll(name string) error {
+	if m.isMountPoint(name) {
+		return &fs.PathError{Op: "removeall", Path: name, Err: syscall.EBUSY}
+	}
+	fsys, p, dir := m.resolve(name)
+	return outer(fsys.RemoveAll(p), dir)
+}
+
+func (m *Mux) Rename(oldName, newName string) error {
+	if m.isMountPoint(oldName) || m.isMountPoint(newName) {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: syscall.EBUSY}
+	}
+	src, op, sdir := m.resolve(oldName)
+	_, np, ddir := m.resolve(newName)
+	if sdir != ddir {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: syscall.EXDEV}
+	}
+	return outer(src.Rename(op, np), sdir)
+}
+
+func (m *Mux) Chmod(name string, mode fs.FileMode) error {
+	fsys, p, dir := m.resolve(name)
+	return outer(fsys.Chmod(p, mode), dir)
+}
+
+func (m *Mux) Chtimes(name string, atime, mtime time.Time) error {
+	fsys, p, dir := m.resolve(name)
+	return outer(fsys.Chtimes(p, atime, mtime), dir)
+}
@@ -0,0 +1,620 @@
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	// defaultPartSize 이보다 큰 객체는 이 크기 조각으로 나눠 올린다 (multipart)
+	defaultPartSize = 16 << 20
+	// maxCopySize 서버 쪽 단일 복사의 한계. 넘으면 조각 복사 (ComposeObject)
+	maxCopySize = 5 << 30
+)
+
+// S3 S3 호환 저장소를 FS로. 버킷과 키 접두사("/"까지)가 디렉터리, 객체가 파일이다.
+// URL에 버킷이 없으면 루트 아래에 버킷들이 보인다.
+// 빈 디렉터리는 "key/" 빈 객체로 남긴다. 마지막 객체를 지우면 디렉터리도 사라진다.
+type S3 struct {
+	client   *minio.Client
+	bucket   string // 비어 있으면 모든 버킷
+	prefix   string // 버킷 안의 루트 ("a/b", 끝에 "/" 없음)
+	region   string
+	partSize uint64
+	ctx      context.Context // WithContext로 만든 것만
+}
+
+type S3Options struct {
+	// Endpoint http(s)://host:port. 없으면 AWS_ENDPOINT_URL_S3, AWS_ENDPOINT_URL, s3.amazonaws.com 순
+	Endpoint  string
+	Region    string
+	AccessKey string // 없으면 AWS_ACCESS_KEY_ID 등 환경 변수와 ~/.aws/credentials
+	SecretKey string
+	PartSize  uint64 // 0이면 16 MiB (최소 5 MiB)
+}
+
+// NewS3 rawURL은 s3://bucket/prefix (s3:// 만 주면 모든 버킷). 연결은 첫 요청에서 맺는다.
+func NewS3(rawURL string, opts S3Options) (*S3, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "s3" {
+		return nil, fmt.Errorf("s3: unsupported url %q", rawURL)
+	}
+
+	endpoint := opts.Endpoint
+	for _, env := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if endpoint == "" {
+			endpoint = os.Getenv(env)
+		}
+	}
+	custom := endpoint != ""
+	if !custom {
+		endpoint = "https://s3.amazonaws.com"
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	ep, err := url.Parse(endpoint)
+	if err != nil || ep.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", endpoint)
+	}
+
+	creds := credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, "")
+	if opts.AccessKey == "" {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.FileAWSCredentials{},
+		})
+	}
+	lookup := minio.BucketLookupAuto
+	if custom {
+		// 로컬 대체 서버는 대개 가상 호스트 방식을 모른다
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(ep.Host, &minio.Options{
+		Creds:        creds,
+		Secure:       ep.Scheme == "https",
+		Region:       opts.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+
+	partSize := opts.PartSize
+	if partSize == 0 {
+		partSize = defaultPartSize
+	}
+	return &S3{
+		client:   client,
+		bucket:   u.Host,
+		prefix:   strings.Trim(u.Path, "/"),
+		region:   opts.Region,
+		partSize: max(partSize, 5<<20),
+	}, nil
+}
+
+func (s *S3) String() string {
+	return "s3://" + path.Join(s.bucket, s.prefix)
+}
+
+func (s *S3) withContext(ctx context.Context) FS {
+	cp := *s
+	cp.ctx = ctx
+	return &cp
+}
+
+// context 요청에 쓸 ctx. 끝나면 목록, 조각 업로드, 스트림 읽기가 멈춘다
+func (s *S3) context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// locate name의 버킷과 키. 루트면 key == "" (모든 버킷 모드의 루트면 bucket도 "")
+func (s *S3) locate(name string) (bucket, key string) {
+	p := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+	if s.bucket != "" {
+		return s.bucket, path.Join(s.prefix, p)
+	}
+	bucket, key, _ = strings.Cut(p, "/")
+	return bucket, key
+}
+
+// isRoot FS의 루트 (지우거나 옮길 수 없다)
+func (s *S3) isRoot(bucket, key string) bool {
+	return bucket == "" || (s.bucket != "" && key == s.prefix)
+}
+
+// isBucket 루트나 버킷 자신 (객체가 아니다)
+func (s *S3) isBucket(bucket, key string) bool {
+	return s.isRoot(bucket, key) || key == ""
+}
+
+// s3Err minio 오류를 fs 오류로
+func s3Err(op, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &fs.PathError{Op: op, Path: name, Err: s3Cause(err)}
+}
+
+func s3Cause(err error) error {
+	resp := minio.ToErrorResponse(err)
+	switch {
+	case resp.Code == "NoSuchKey" || resp.Code == "NoSuchBucket" || resp.Code == "NotFound" || resp.StatusCode == 404:
+		return fs.ErrNotExist
+	case resp.Code == "AccessDenied" || resp.StatusCode == 403:
+		return fs.ErrPermission
+	case resp.Code == "BucketAlreadyOwnedByYou" || resp.Code == "BucketAlreadyExists":
+		return fs.ErrExist
+	case resp.Code == "BucketNotEmpty":
+		return syscall.ENOTEMPTY
+	}
+	return err
+}
+
+// putOptions http 엔드포인트에서 minio가 쓰는 aws-chunked 서명은 호환 서버들이 잘 모른다.
+// 본문 서명 대신 Content-Length와 체크섬으로 올린다.
+func (s *S3) putOptions() minio.PutObjectOptions {
+	return minio.PutObjectOptions{PartSize: s.partSize, DisableContentSha256: true}
+}
+
+func dirInfo(name string, modTime time.Time) fs.FileInfo {
+	return memInfo{name: name, mode: fs.ModeDir | 0o755, modTime: modTime}
+}
+
+func objInfo(name string, o minio.ObjectInfo) fs.FileInfo {
+	return memInfo{name: name, size: o.Size, mode: 0o644, modTime: o.LastModified}
+}
+
+// hasPrefix dir/ 아래에 객체가 하나라도 있는지 (디렉터리 표시 객체 포함)
+func (s *S3) hasPrefix(bucket, dir string) (bool, error) {
+	ctx, cancel := context.WithCancel(s.context())
+	defer cancel()
+	for o := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: dir, MaxKeys: 1}) {
+		return o.Err == nil, o.Err
+	}
+	return false, nil
+}
+
+func (s *S3) Stat(name string) (fs.FileInfo, error) {
+	bucket, key := s.locate(name)
+	base := filepath.Base(name)
+	if bucket == "" {
+		return dirInfo(base, time.Time{}), nil
+	}
+	ctx := s.context()
+	if s.isBucket(bucket, key) {
+		ok, err := s.client.BucketExists(ctx, bucket)
+		if err != nil {
+			return nil, s3Err("stat", name, err)
+		}
+		if !ok {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		return dirInfo(base, time.Time{}), nil
+	}
+
+	o, err := s.client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		return objInfo(base, o), nil
+	}
+	if err = s3Err("stat", name, err); !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	ok, lErr := s.hasPrefix(bucket, key+"/")
+	if lErr != nil {
+		return nil, s3Err("stat", name, lErr)
+	}
+	if !ok {
+		return nil, err
+	}
+	return dirInfo(base, time.Time{}), nil
+}
+
+func (s *S3) Lstat(name string) (fs.FileInfo, error) {
+	return s.Stat(name)
+}
+
+func (s *S3) ReadDir(name string) ([]fs.DirEntry, error) {
+	bucket, key := s.locate(name)
+	ctx := s.context()
+	if bucket == "" {
+		bs, err := s.client.ListBuckets(ctx)
+		if err != nil {
+			return nil, s3Err("readdir", name, err)
+		}
+		ents := make([]fs.DirEntry, len(bs))
+		for i, b := range bs {
+			ents[i] = fs.FileInfoToDirEntry(dirInfo(b.Name, b.CreationDate))
+		}
+		sortEntries(ents)
+		return ents, nil
+	}
+
+	prefix := ""
+	if key != "" {
+		prefix = key + "/"
+	}
+	var ents []fs.DirEntry
+	self := false
+	for o := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if o.Err != nil {
+			return nil, s3Err("readdir", name, o.Err)
+		}
+		rel := strings.TrimPrefix(o.Key, prefix)
+		switch {
+		case rel == "":
+			self = true
+		case strings.HasSuffix(rel, "/"):
+			ents = append(ents, fs.FileInfoToDirEntry(dirInfo(strings.TrimSuffix(rel, "/"), o.LastModified)))
+		default:
+			ents = append(ents, fs.FileInfoToDirEntry(objInfo(rel, o)))
+		}
+	}
+
+	// 비어 있으면 빈 디렉터리인지, 없는지, 파일인지
+	if len(ents) == 0 && !self {
+		fi, err := s.Stat(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+		}
+		if !fi.IsDir() {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: syscall.ENOTDIR}
+		}
+	}
+	sortEntries(ents)
+	return ents, nil
+}
+
+func (s *S3) Open(name string) (File, error) {
+	bucket, key := s.locate(name)
+	if s.isBucket(bucket, key) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	}
+	obj, err := s.client.GetObject(s.context(), bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Err("open", name, err)
+	}
+	// GetObject는 첫 요청을 미룬다. 여기서 없는 키를 알아낸다.
+	info, err := obj.Stat()
+	if err != nil {
+		_ = obj.Close()
+		err = s3Err("open", name, err)
+		if fi, sErr := s.Stat(name); sErr == nil && fi.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		return nil, err
+	}
+	return &s3Reader{name: name, obj: obj, info: objInfo(filepath.Base(name), info)}, nil
+}
+
+// OpenFile 쓰기는 닫을 때 올린다. 조각 크기를 넘으면 그때부터 multipart로 흘려보낸다.
+// 객체는 고칠 수 없어서 O_APPEND는 기존 내용을 앞에 붙여 다시 올린다 (O_RDWR는 지원하지 않음).
+func (s *S3) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return s.Open(name)
+	}
+	if flag&os.O_RDWR != 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+	}
+	bucket, key := s.locate(name)
+	if s.isBucket(bucket, key) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	}
+
+	fi, err := s.Stat(name)
+	exists := err == nil
+	switch {
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	case exists && fi.IsDir():
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	case exists && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case !exists && flag&os.O_CREATE == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	f := &s3Writer{s: s, name: name, bucket: bucket, key: key, mustPut: !exists || flag&os.O_TRUNC != 0}
+	if exists && flag&os.O_APPEND != 0 && flag&os.O_TRUNC == 0 {
+		r, err := s.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		f.prefix = r
+	}
+	return f, nil
+}
+
+// Mkdir 모든 버킷 모드의 루트 바로 아래면 버킷을 만든다
+func (s *S3) Mkdir(name string, _ fs.FileMode) error {
+	bucket, key := s.locate(name)
+	ctx := s.context()
+	if s.isRoot(bucket, key) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if key == "" {
+		return s3Err("mkdir", name, s.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: s.region}))
+	}
+
+	if _, err := s.Stat(name); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if parent, err := s.Stat(filepath.Dir(name)); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrNotExist}
+	} else if !parent.IsDir() {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	}
+	_, err := s.client.PutObject(ctx, bucket, key+"/", bytes.NewReader(nil), 0, s.putOptions())
+	return s3Err("mkdir", name, err)
+}
+
+func (s *S3) MkdirAll(name string, perm fs.FileMode) error {
+	fi, err := s.Stat(name)
+	switch {
+	case err == nil && fi.IsDir():
+		return nil
+	case err == nil:
+		return &fs.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	if parent := filepath.Dir(name); parent != name {
+		if err = s.MkdirAll(parent, perm); err != nil {
+			return err
+		}
+	}
+	if err = s.Mkdir(name, perm); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *S3) Remove(name string) error {
+	bucket, key := s.locate(name)
+	ctx := s.context()
+	if s.isRoot(bucket, key) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+	}
+	if key == "" {
+		return s3Err("remove", name, s.client.RemoveBucket(ctx, bucket))
+	}
+
+	fi, err := s.Stat(name)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		ents, err := s.ReadDir(name)
+		if err != nil {
+			return err
+		}
+		if len(ents) > 0 {
+			return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+		key += "/"
+	}
+	return s3Err("remove", name, s.client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{}))
+}
+
+// RemoveAll 접두사 아래의 객체를 한꺼번에 지운다 (버킷이면 버킷까지)
+func (s *S3) RemoveAll(name string) error {
+	bucket, key := s.locate(name)
+	ctx := s.context()
+	if s.isRoot(bucket, key) {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrPermission}
+	}
+
+	prefix := ""
+	if key != "" {
+		prefix = key + "/"
+		err := s.client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{})
+		if err = s3Err("removeall", name, err); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	objs := s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	for rErr := range s.client.RemoveObjects(ctx, bucket, objs, minio.RemoveObjectsOptions{}) {
+		if err := s3Err("removeall", name, rErr.Err); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if key == "" {
+		if err := s3Err("removeall", name, s.client.RemoveBucket(ctx, bucket)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rename 서버 쪽 복사 후 삭제. 버킷 자체는 옮길 수 없어 EXDEV (mv가 복사로 넘어간다).
+func (s *S3) Rename(oldName, newName string) error {
+	linkErr := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: err}
+	}
+	sb, sk := s.locate(oldName)
+	db, dk := s.locate(newName)
+	if s.isBucket(sb, sk) || s.isBucket(db, dk) {
+		return linkErr(syscall.EXDEV)
+	}
+	src, err := s.Stat(oldName)
+	if err != nil {
+		return linkErr(fs.ErrNotExist)
+	}
+	if dst, err := s.Stat(newName); err == nil {
+		switch {
+		case dst.IsDir() && !src.IsDir():
+			return linkErr(syscall.EISDIR)
+		case !dst.IsDir() && src.IsDir():
+			return linkErr(syscall.ENOTDIR)
+		case dst.IsDir():
+			if ents, err := s.ReadDir(newName); err != nil || len(ents) > 0 {
+				return linkErr(syscall.ENOTEMPTY)
+			}
+		}
+	}
+
+	ctx := s.context()
+	if !src.IsDir() {
+		if err = s.copyObject(ctx, sb, sk, db, dk, src.Size()); err == nil {
+			err = s.client.RemoveObject(ctx, sb, sk, minio.RemoveObjectOptions{})
+		}
+		if err != nil {
+			return linkErr(s3Cause(err))
+		}
+		return nil
+	}
+
+	var moved []minio.ObjectInfo
+	for o := range s.client.ListObjects(ctx, sb, minio.ListObjectsOptions{Prefix: sk + "/", Recursive: true}) {
+		if o.Err == nil {
+			o.Err = s.copyObject(ctx, sb, o.Key, db, dk+"/"+strings.TrimPrefix(o.Key, sk+"/"), o.Size)
+		}
+		if o.Err != nil {
+			return linkErr(s3Cause(o.Err))
+		}
+		moved = append(moved, o)
+	}
+	for _, o := range moved {
+		if err := s.client.RemoveObject(ctx, sb, o.Key, minio.RemoveObjectOptions{}); err != nil {
+			return linkErr(s3Cause(err))
+		}
+	}
+	return nil
+}
+
+// copyObject 서버 쪽 복사. 5 GiB가 넘으면 조각 단위로
+func (s *S3) copyObject(ctx context.Context, sb, sk, db, dk string, size int64) error {
+	dst := minio.CopyDestOptions{Bucket: db, Object: dk}
+	src := minio.CopySrcOptions{Bucket: sb, Object: sk}
+	var err error
+	if size > maxCopySize {
+		_, err = s.client.ComposeObject(ctx, dst, src)
+	} else {
+		_, err = s.client.CopyObject(ctx, dst, src)
+	}
+	return err
+}
+
+func (s *S3) Chmod(name string, _ fs.FileMode) error {
+	return &fs.PathError{Op: "chmod", Path: name, Err: errors.ErrUnsupported}
+}
+
+// Chtimes 수정 시각은 서버가 정한다. 있는지만 확인한다.
+func (s *S3) Chtimes(name string, _, _ time.Time) error {
+	_, err := s.Stat(name)
+	return err
+}
+
+// s3Reader GetObject 스트림
+type s3Reader struct {
+	name string
+	obj  *minio.Object
+	info fs.FileInfo
+}
+
+func (f *s3Reader) Read(p []byte) (int, error) { return f.obj.Read(p) }
+
+func (f *s3Reader) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+}
+
+func (f *s3Reader) Close() error { return f.obj.Close() }
+
+func (f *s3Reader) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+// s3Writer 조각 크기까지는 모아 두었다가 PutObject 한 번으로,
+// 넘으면 PutObject(크기 모름)를 시작해 multipart로 흘려보낸다.
+type s3Writer struct {
+	s           *S3
+	name        string
+	bucket, key string
+	prefix      File // O_APPEND: 기존 내용
+	mustPut     bool // 쓴 게 없어도 닫을 때 올린다 (새 파일, O_TRUNC)
+
+	buf    bytes.Buffer
+	pw     *io.PipeWriter
+	done   chan error
+	closed bool
+}
+
+func (f *s3Writer) start() {
+	pr, pw := io.Pipe()
+	f.pw, f.done = pw, make(chan error, 1)
+
+	var body io.Reader = io.MultiReader(&f.buf, pr)
+	if f.prefix != nil {
+		body = io.MultiReader(f.prefix, body)
+	}
+	go func() {
+		_, err := f.s.client.PutObject(f.s.context(), f.bucket, f.key, body, -1, f.s.putOptions())
+		err = s3Err("write", f.name, err)
+		// 실패하면 쓰던 쪽도 같은 오류로 멈춘다
+		_ = pr.CloseWithError(err)
+		f.done <- err
+	}()
+}
+
+func (f *s3Writer) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrPermission}
+}
+
+func (f *s3Writer) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.pw != nil {
+		return f.pw.Write(p)
+	}
+	f.buf.Write(p)
+	if uint64(f.buf.Len()) > f.s.partSize {
+		f.start()
+	}
+	return len(p), nil
+}
+
+func (f *s3Writer) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	if f.prefix != nil {
+		defer func() { _ = f.prefix.Close() }()
+	}
+	if f.pw == nil {
+		switch {
+		case f.buf.Len() == 0 && !f.mustPut:
+			return nil
+		case f.prefix != nil:
+			// 기존 내용 길이는 모르므로 흘려보낸다
+			f.start()
+		default:
+			_, err := f.s.client.PutObject(f.s.context(), f.bucket, f.key,
+				bytes.NewReader(f.buf.Bytes()), int64(f.buf.Len()), f.s.putOptions())
+			return s3Err("write", f.name, err)
+		}
+	}
+	_ = f.pw.Close()
+	return <-f.done
+}
+
+func (f *s3Writer) Stat() (fs.FileInfo, error) { return f.s.Stat(f.name) }
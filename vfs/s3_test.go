@@ -0,0 +1,352 @@
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeS3 한 버킷만 있는 S3 대역. 경로 방식 요청만 받는다 (목록 v2, multipart, 일괄 삭제, 서버 쪽 복사)
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte // uploadId → 조각 번호 → 내용
+	parts   int                       // 받은 조각 수
+	gets    int                       // 받은 GET 수
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, *httptest.Server) {
+	t.Helper()
+	f := &fakeS3{bucket: bucket, objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func etag(b []byte) string {
+	sum := md5.Sum(b)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func (f *fakeS3) fail(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		f.fail(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	q := r.URL.Query()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case key == "" && r.Method == http.MethodGet && q.Has("location"):
+		_, _ = io.WriteString(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+	case key == "" && r.Method == http.MethodHead:
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, q)
+	case key == "" && r.Method == http.MethodPost && q.Has("delete"):
+		f.deleteObjects(w, r)
+	case r.Method == http.MethodHead, r.Method == http.MethodGet:
+		b, ok := f.objects[key]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.Header().Set("ETag", etag(b))
+		w.Header().Set("Last-Modified", time.Unix(0, 0).UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			f.gets++
+			_, _ = w.Write(b)
+		}
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := strconv.Itoa(len(f.uploads) + 1)
+		f.uploads[id] = map[int][]byte{}
+		_, _ = fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", bucket, key, id)
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		parts, ok := f.uploads[q.Get("uploadId")]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		nums := make([]int, 0, len(parts))
+		for n := range parts {
+			nums = append(nums, n)
+		}
+		sort.Ints(nums)
+		var all []byte
+		for _, n := range nums {
+			all = append(all, parts[n]...)
+		}
+		f.objects[key] = all
+		delete(f.uploads, q.Get("uploadId"))
+		_, _ = fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>%s</ETag></CompleteMultipartUploadResult>", bucket, key, etag(all))
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		src, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+		_, srcKey, _ := strings.Cut(strings.TrimPrefix(src, "/"), "/")
+		b, ok := f.objects[srcKey]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		f.objects[key] = append([]byte(nil), b...)
+		_, _ = fmt.Fprintf(w, "<CopyObjectResult><ETag>%s</ETag><LastModified>%s</LastModified></CopyObjectResult>", etag(b), time.Unix(0, 0).UTC().Format(time.RFC3339))
+	case r.Method == http.MethodPut:
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			f.fail(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		if id := q.Get("uploadId"); id != "" {
+			n, _ := strconv.Atoi(q.Get("partNumber"))
+			f.uploads[id][n] = b
+			f.parts++
+		} else {
+			f.objects[key] = b
+		}
+		w.Header().Set("ETag", etag(b))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.fail(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// list ListObjectsV2. delimiter가 있으면 하위 접두사를 CommonPrefixes로 묶는다
+func (f *fakeS3) list(w http.ResponseWriter, q url.Values) {
+	prefix, delim := q.Get("prefix"), q.Get("delimiter")
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("<ListBucketResult><Name>" + f.bucket + "</Name><IsTruncated>false</IsTruncated>")
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if delim != "" {
+			if i := strings.Index(k[len(prefix):], delim); i >= 0 {
+				p := k[:len(prefix)+i+len(delim)]
+				if !seen[p] {
+					seen[p] = true
+					buf.WriteString("<CommonPrefixes><Prefix>" + p + "</Prefix></CommonPrefixes>")
+				}
+				continue
+			}
+		}
+		fmt.Fprintf(&buf, "<Contents><Key>%s</Key><Size>%d</Size><ETag>%s</ETag><LastModified>%s</LastModified></Contents>",
+			k, len(f.objects[k]), etag(f.objects[k]), time.Unix(0, 0).UTC().Format(time.RFC3339))
+	}
+	buf.WriteString("</ListBucketResult>")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (f *fakeS3) deleteObjects(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Objects []struct {
+			Key string `xml:"Key"`
+		} `xml:"Object"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		f.fail(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	var buf bytes.Buffer
+	buf.WriteString("<DeleteResult>")
+	for _, o := range req.Objects {
+		delete(f.objects, o.Key)
+		buf.WriteString("<Deleted><Key>" + o.Key + "</Key></Deleted>")
+	}
+	buf.WriteString("</DeleteResult>")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3) {
+	t.Helper()
+	f, srv := newFakeS3(t, "bkt")
+	s, err := NewS3("s3://bkt/root", S3Options{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "key",
+		SecretKey: "secret",
+		PartSize:  5 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, f
+}
+
+func TestS3List(t *testing.T) {
+	s, f := newTestS3(t)
+	f.objects["root/a.txt"] = []byte("a")
+	f.objects["root/d/"] = nil
+	f.objects["root/d/b.txt"] = []byte("bb")
+	f.objects["root/e/f/c.txt"] = []byte("c") // 표시 객체 없는 디렉터리
+	f.objects["other.txt"] = []byte("x")      // 접두사 밖
+
+	ents, err := s.ReadDir("/")
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var got []string
+	for _, e := range ents {
+		got = append(got, fmt.Sprintf("%s:%v", e.Name(), e.IsDir()))
+	}
+	if want := "a.txt:false d:true e:true"; strings.Join(got, " ") != want {
+		t.Errorf("ReadDir / = %v, want %s", got, want)
+	}
+	if ents, err = s.ReadDir("/d"); err != nil || len(ents) != 1 || ents[0].Name() != "b.txt" {
+		t.Errorf("ReadDir /d = %v, %v", ents, err)
+	}
+	if _, err = s.ReadDir("/a.txt"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("ReadDir file = %v, want ENOTDIR", err)
+	}
+	if _, err = s.ReadDir("/nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadDir missing = %v, want ErrNotExist", err)
+	}
+
+	if fi, err := s.Stat("/e/f"); err != nil || !fi.IsDir() {
+		t.Errorf("Stat implicit dir = %v, %v", fi, err)
+	}
+	if fi, err := s.Stat("/d/b.txt"); err != nil || fi.IsDir() || fi.Size() != 2 {
+		t.Errorf("Stat file = %v, %v", fi, err)
+	}
+	if _, err := s.Stat("/nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat missing = %v, want ErrNotExist", err)
+	}
+
+	if err := s.Mkdir("/g", 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	if _, ok := f.objects["root/g/"]; !ok {
+		t.Error("Mkdir did not leave a marker object")
+	}
+	if err := s.Mkdir("/d", 0o755); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkdir existing = %v, want ErrExist", err)
+	}
+
+	if err := s.Rename("/d", "/h"); err != nil {
+		t.Fatalf("Rename dir: %v", err)
+	}
+	if _, ok := f.objects["root/h/b.txt"]; !ok || Exists(s, "/d") {
+		t.Errorf("Rename dir left %v", f.objects)
+	}
+	if err := s.RemoveAll("/e"); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if Exists(s, "/e") {
+		t.Error("RemoveAll left /e")
+	}
+	if err := s.RemoveAll("/"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("RemoveAll root = %v, want ErrPermission", err)
+	}
+	if _, ok := f.objects["other.txt"]; !ok {
+		t.Error("object outside the prefix was touched")
+	}
+}
+
+func TestS3MultipartUpload(t *testing.T) {
+	s, f := newTestS3(t)
+
+	// 조각 크기(5 MiB)보다 크면 multipart로 흘려보낸다
+	data := bytes.Repeat([]byte("0123456789abcdef"), (12<<20)/16)
+	w, err := s.OpenFile("/big.bin", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range slices.Chunk(data, 1<<20) {
+		if _, err = w.Write(chunk); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if f.parts < 2 {
+		t.Errorf("uploaded %d parts, want multipart", f.parts)
+	}
+	if !bytes.Equal(f.objects["root/big.bin"], data) {
+		t.Errorf("stored %d bytes, want %d", len(f.objects["root/big.bin"]), len(data))
+	}
+
+	// 작은 파일은 PutObject 한 번
+	parts := f.parts
+	if err = WriteFile(s, "/small.txt", []byte("small"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if f.parts != parts || string(f.objects["root/small.txt"]) != "small" {
+		t.Errorf("small upload: parts %d→%d, stored %q", parts, f.parts, f.objects["root/small.txt"])
+	}
+}
+
+func TestS3StreamRead(t *testing.T) {
+	s, f := newTestS3(t)
+	data := bytes.Repeat([]byte("x"), 3<<20)
+	f.objects["root/r.bin"] = data
+
+	r, err := s.Open("/r.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	if fi, err := r.Stat(); err != nil || fi.Size() != int64(len(data)) {
+		t.Errorf("Stat = %v, %v", fi, err)
+	}
+	n, err := io.Copy(io.Discard, r)
+	if err != nil || n != int64(len(data)) {
+		t.Errorf("read %d, %v; want %d", n, err, len(data))
+	}
+	if f.gets != 1 {
+		t.Errorf("%d GET requests, want one stream", f.gets)
+	}
+
+	if _, err = s.Open("/nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open missing = %v, want ErrNotExist", err)
+	}
+}
+
+func TestS3Context(t *testing.T) {
+	s, f := newTestS3(t)
+	f.objects["root/a.txt"] = []byte("a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WithContext(ctx, s).ReadDir("/"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadDir with canceled ctx = %v, want context.Canceled", err)
+	}
+	if _, err := s.ReadDir("/"); err != nil {
+		t.Errorf("ReadDir = %v", err)
+	}
+}
@@ -0,0 +1,515 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTP SSH 서버의 한 디렉터리를 루트로 보는 FS
+type SFTP struct {
+	client *sftp.Client
+	conn   *ssh.Client     // DialSFTP로 연결했을 때만. Close에서 닫는다
+	agent  net.Conn        // SSH 에이전트 연결 (DialSFTP, 에이전트가 있을 때만). Close에서 닫는다
+	root   string          // 원격 절대 경로
+	ctx    context.Context // WithContext로 만든 것만
+}
+
+type SFTPOptions struct {
+	Password string   // 에이전트와 키로 안 될 때
+	KeyFiles []string // 없으면 ~/.ssh/id_ed25519, id_ecdsa, id_rsa
+	// KnownHosts 호스트 키 확인 파일. 없으면 ~/.ssh/known_hosts
+	KnownHosts string
+	// HostKeyCallback 있으면 KnownHosts 대신 (테스트용)
+	HostKeyCallback ssh.HostKeyCallback
+	Timeout         time.Duration // 연결과 핸드셰이크 시간 제한. 0이면 15초
+}
+
+// DialSFTP sftp://user@host:port/path 에 연결한다. 인증은 SSH 에이전트, 키 파일, 암호 순.
+// 경로가 없으면 원격 사용자의 홈 디렉터리가 루트다. ctx가 끝나면 연결을 끊고 ctx 오류를 돌려준다.
+func DialSFTP(ctx context.Context, rawURL string, opts SFTPOptions) (*SFTP, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "sftp" || u.Host == "" {
+		return nil, fmt.Errorf("sftp: unsupported url %q", rawURL)
+	}
+
+	name := ""
+	if u.User != nil {
+		name = u.User.Username()
+		if pw, ok := u.User.Password(); ok && opts.Password == "" {
+			opts.Password = pw
+		}
+	}
+	if name == "" {
+		cur, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		name = cur.Username
+	}
+
+	hostKey := opts.HostKeyCallback
+	if hostKey == nil {
+		if hostKey, err = knownHostsCallback(opts.KnownHosts); err != nil {
+			return nil, err
+		}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+	auth, agentConn := authMethods(opts)
+	closeAgent := func() {
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+	}
+	tcp, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		closeAgent()
+		return nil, fmt.Errorf("sftp: %w", err)
+	}
+	// 핸드셰이크부터 루트 확인까지도 timeout과 ctx에 묶는다
+	_ = tcp.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { _ = tcp.Close() })
+	s, err := handshakeSFTP(tcp, addr, u.Path, &ssh.ClientConfig{
+		User:            name,
+		Auth:            auth,
+		HostKeyCallback: hostKey,
+	})
+	if !stop() {
+		if err == nil {
+			_ = s.Close()
+		}
+		err = fmt.Errorf("sftp: %w", ctx.Err())
+	}
+	if err != nil {
+		_ = tcp.Close()
+		closeAgent()
+		return nil, err
+	}
+	_ = tcp.SetDeadline(time.Time{})
+	s.agent = agentConn
+	return s, nil
+}
+
+// handshakeSFTP 이미 연결된 tcp 위에서 SSH 인증과 SFTP 세션을 연다
+func handshakeSFTP(tcp net.Conn, addr, root string, cfg *ssh.ClientConfig) (*SFTP, error) {
+	c, chans, reqs, err := ssh.NewClientConn(tcp, addr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: %w", err)
+	}
+	conn := ssh.NewClient(c, chans, reqs)
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("sftp: %w", err)
+	}
+	s, err := NewSFTP(client, root)
+	if err != nil {
+		_ = client.Close()
+		_ = conn.Close()
+		return nil, err
+	}
+	s.conn = conn
+	return s, nil
+}
+
+// NewSFTP 이미 연결된 client의 root를 루트로. root가 비어 있으면 홈 디렉터리
+func NewSFTP(client *sftp.Client, root string) (*SFTP, error) {
+	if root == "" {
+		home, err := client.RealPath(".")
+		if err != nil {
+			return nil, fmt.Errorf("sftp: %w", err)
+		}
+		root = home
+	}
+	return &SFTP{client: client, root: path.Clean(root)}, nil
+}
+
+// authMethods 에이전트 → 키 파일 → 암호. 암호가 걸린 키는 건너뛴다 (에이전트에 올려 두면 된다).
+// agentConn은 에이전트에 연결했을 때만. 연결을 다 쓰면 부른 쪽이 닫는다
+func authMethods(opts SFTPOptions) (methods []ssh.AuthMethod, agentConn net.Conn) {
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			agentConn = conn
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	keyFiles := opts.KeyFiles
+	if len(keyFiles) == 0 {
+		if home, err := os.UserHomeDir(); err == nil {
+			for _, n := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+				keyFiles = append(keyFiles, filepath.Join(home, ".ssh", n))
+			}
+		}
+	}
+	var signers []ssh.Signer
+	for _, fp := range keyFiles {
+		data, err := os.ReadFile(fp)
+		if err != nil {
+			continue
+		}
+		if signer, err := ssh.ParsePrivateKey(data); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if opts.Password != "" {
+		methods = append(methods, ssh.Password(opts.Password))
+	}
+	return methods, agentConn
+}
+
+// knownHostsCallback known_hosts에 없는 호스트는 거부한다 (먼저 ssh로 한 번 접속해 등록)
+func knownHostsCallback(file string) (ssh.HostKeyCallback, error) {
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	cb, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: known_hosts: %w", err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := cb(hostname, remote, key)
+		var ke *knownhosts.KeyError
+		if errors.As(err, &ke) {
+			if len(ke.Want) == 0 {
+				return fmt.Errorf("unknown host %s: not in %s", hostname, file)
+			}
+			return fmt.Errorf("host key mismatch for %s (%s)", hostname, file)
+		}
+		return err
+	}, nil
+}
+
+func (s *SFTP) String() string { return "sftp:" + s.root }
+
+func (s *SFTP) withContext(ctx context.Context) FS {
+	cp := *s
+	cp.ctx = ctx
+	return &cp
+}
+
+// sftpHangGrace 취소된 요청이 이만큼 지나도 끝나지 않으면 연결이 멈춘 것으로 보고 닫는다
+var sftpHangGrace = 5 * time.Second
+
+// do f를 ctx에 묶는다. SFTP 요청은 취소할 수 없으므로 ctx가 끝나면 기다리지 않고 돌아오고,
+// 그래도 f가 sftpHangGrace 안에 끝나지 않으면 연결을 닫는다 (다시 connect해야 한다).
+// ctx 오류를 돌려줄 때 f가 쓰는 변수는 아직 쓰이는 중이므로 부른 쪽은 읽지 않는다
+func (s *SFTP) do(f func() error) error {
+	if s.ctx == nil || s.ctx.Done() == nil {
+		return f()
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- f() }()
+	select {
+	case err := <-done:
+		return err
+	case <-s.ctx.Done():
+		go func() {
+			t := time.NewTimer(sftpHangGrace)
+			defer t.Stop()
+			select {
+			case <-done:
+			case <-t.C:
+				_ = s.Close()
+			}
+		}()
+		return s.ctx.Err()
+	}
+}
+
+// Close 연결을 닫는다 (Mux.Unmount가 부른다)
+func (s *SFTP) Close() error {
+	err := s.client.Close()
+	if s.conn != nil {
+		if cErr := s.conn.Close(); err == nil {
+			err = cErr
+		}
+	}
+	if s.agent != nil {
+		_ = s.agent.Close()
+	}
+	return err
+}
+
+func (s *SFTP) path(name string) string {
+	return path.Join(s.root, filepath.ToSlash(name))
+}
+
+// sftpErr 오류 속 원격 경로를 FS 안의 경로로
+func sftpErr(err error, name string) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		return &fs.PathError{Op: pe.Op, Path: name, Err: pe.Err}
+	}
+	if err != nil {
+		return &fs.PathError{Op: "sftp", Path: name, Err: err}
+	}
+	return nil
+}
+
+func (s *SFTP) Stat(name string) (fs.FileInfo, error) {
+	var fi fs.FileInfo
+	err := s.do(func() (err error) {
+		fi, err = s.client.Stat(s.path(name))
+		return err
+	})
+	if err != nil {
+		return nil, sftpErr(err, name)
+	}
+	return fi, nil
+}
+
+func (s *SFTP) Lstat(name string) (fs.FileInfo, error) {
+	var fi fs.FileInfo
+	err := s.do(func() (err error) {
+		fi, err = s.client.Lstat(s.path(name))
+		return err
+	})
+	if err != nil {
+		return nil, sftpErr(err, name)
+	}
+	return fi, nil
+}
+
+func (s *SFTP) ReadDir(name string) ([]fs.DirEntry, error) {
+	var fis []fs.FileInfo
+	err := s.do(func() (err error) {
+		fis, err = s.client.ReadDir(s.path(name))
+		return err
+	})
+	if err != nil {
+		return nil, sftpErr(err, name)
+	}
+	ents := make([]fs.DirEntry, len(fis))
+	for i, fi := range fis {
+		ents[i] = fs.FileInfoToDirEntry(fi)
+	}
+	sortEntries(ents)
+	return ents, nil
+}
+
+func (s *SFTP) Open(name string) (File, error) {
+	var f *sftp.File
+	err := s.do(func() (err error) {
+		f, err = s.client.Open(s.path(name))
+		return err
+	})
+	if err != nil {
+		return nil, sftpErr(err, name)
+	}
+	return &sftpFile{f: f, s: s}, nil
+}
+
+func (s *SFTP) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	p := s.path(name)
+	var f *sftp.File
+	err := s.do(func() (err error) {
+		created := false
+		if flag&os.O_CREATE != 0 {
+			_, err := s.client.Lstat(p)
+			created = errors.Is(err, fs.ErrNotExist)
+		}
+		if f, err = s.client.OpenFile(p, flag); err != nil {
+			return err
+		}
+		if created {
+			// SFTP open은 권한을 받지 않는다
+			_ = s.client.Chmod(p, perm.Perm())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, sftpErr(err, name)
+	}
+	return &sftpFile{f: f, s: s}, nil
+}
+
+func (s *SFTP) Mkdir(name string, perm fs.FileMode) error {
+	p := s.path(name)
+	return sftpErr(s.do(func() error {
+		if err := s.client.Mkdir(p); err != nil {
+			// 이미 있으면 서버는 대개 일반 실패만 돌려준다
+			if fi, sErr := s.client.Lstat(p); sErr == nil && fi != nil {
+				return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+			}
+			return err
+		}
+		_ = s.client.Chmod(p, perm.Perm())
+		return nil
+	}), name)
+}
+
+func (s *SFTP) MkdirAll(name string, _ fs.FileMode) error {
+	return sftpErr(s.do(func() error { return s.client.MkdirAll(s.path(name)) }), name)
+}
+
+func (s *SFTP) Remove(name string) error {
+	return sftpErr(s.do(func() error { return s.client.Remove(s.path(name)) }), name)
+}
+
+func (s *SFTP) RemoveAll(name string) error {
+	p := s.path(name)
+	if p == s.root {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrPermission}
+	}
+	return sftpErr(s.do(func() error {
+		if _, err := s.client.Lstat(p); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return s.client.RemoveAll(p)
+	}), name)
+}
+
+// Rename 서버가 지원하면 posix-rename (대상이 있으면 덮어쓴다)
+func (s *SFTP) Rename(oldName, newName string) error {
+	rename := s.client.Rename
+	if _, ok := s.client.HasExtension("posix-rename@openssh.com"); ok {
+		rename = s.client.PosixRename
+	}
+	if err := s.do(func() error { return rename(s.path(oldName), s.path(newName)) }); err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			err = pe.Err
+		}
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: err}
+	}
+	return nil
+}
+
+func (s *SFTP) Chmod(name string, mode fs.FileMode) error {
+	return sftpErr(s.do(func() error { return s.client.Chmod(s.path(name), mode) }), name)
+}
+
+// Chtimes SFTP는 두 시각을 함께 바꾸므로 zero time은 지금 값으로 채운다
+func (s *SFTP) Chtimes(name string, atime, mtime time.Time) error {
+	p := s.path(name)
+	return sftpErr(s.do(func() error {
+		if atime.IsZero() || mtime.IsZero() {
+			fi, err := s.client.Stat(p)
+			if err != nil {
+				return err
+			}
+			if mtime.IsZero() {
+				mtime = fi.ModTime()
+			}
+			if atime.IsZero() {
+				atime = mtime
+				if st, ok := fi.Sys().(*sftp.FileStat); ok {
+					atime = time.Unix(int64(st.Atime), 0)
+				}
+			}
+		}
+		return s.client.Chtimes(p, atime, mtime)
+	}), name)
+}
+
+// abandoned do가 ctx 때문에 f를 기다리지 않고 돌아왔는지
+func (s *SFTP) abandoned(err error) bool {
+	return err != nil && s.ctx != nil && err == s.ctx.Err()
+}
+
+// sftpFile 읽기/쓰기 한 번이 멈춰도 ctx 취소로 빠져나오도록 do를 거친다.
+// 버려진 요청이 나중에 부른 쪽 버퍼를 건드리지 않도록 ctx가 있으면 따로 둔 버퍼를 쓴다
+type sftpFile struct {
+	f *sftp.File
+	s *SFTP
+}
+
+func (f *sftpFile) Read(p []byte) (int, error) {
+	if f.s.ctx == nil {
+		return f.f.Read(p)
+	}
+	buf := make([]byte, len(p))
+	var n int
+	err := f.s.do(func() (err error) {
+		n, err = f.f.Read(buf)
+		return err
+	})
+	if f.s.abandoned(err) {
+		return 0, err
+	}
+	return copy(p, buf[:n]), err
+}
+
+// ReadAt 아카이브 안을 볼 때 (zip)
+func (f *sftpFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.s.ctx == nil {
+		return f.f.ReadAt(p, off)
+	}
+	buf := make([]byte, len(p))
+	var n int
+	err := f.s.do(func() (err error) {
+		n, err = f.f.ReadAt(buf, off)
+		return err
+	})
+	if f.s.abandoned(err) {
+		return 0, err
+	}
+	return copy(p, buf[:n]), err
+}
+
+func (f *sftpFile) Write(p []byte) (int, error) {
+	if f.s.ctx == nil {
+		return f.f.Write(p)
+	}
+	buf := append([]byte(nil), p...)
+	var n int
+	err := f.s.do(func() (err error) {
+		n, err = f.f.Write(buf)
+		return err
+	})
+	if f.s.abandoned(err) {
+		return 0, err
+	}
+	return n, err
+}
+
+func (f *sftpFile) Stat() (fs.FileInfo, error) {
+	var fi fs.FileInfo
+	err := f.s.do(func() (err error) {
+		fi, err = f.f.Stat()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fi, nil
+}
+
+// Close 취소된 뒤에도 핸들은 닫는다
+func (f *sftpFile) Close() error { return f.f.Close() }
@@ -0,0 +1,261 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// newTestSFTP net.Pipe 위의 SFTP 서버가 로컬 디스크를 보여 준다. root가 루트
+func newTestSFTP(t *testing.T, root string) *SFTP {
+	t.Helper()
+	cli, srv := net.Pipe()
+	server, err := sftp.NewServer(srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve() }()
+
+	client, err := sftp.NewClientPipe(cli, cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSFTP(client, filepath.ToSlash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.Close()
+		_ = server.Close()
+	})
+	return s
+}
+
+func TestSFTP(t *testing.T) {
+	root := t.TempDir()
+	s := newTestSFTP(t, root)
+
+	if err := s.Mkdir("/d", 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	if err := s.Mkdir("/d", 0o755); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkdir existing = %v, want ErrExist", err)
+	}
+	if err := s.Mkdir("/none/x", 0o755); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Mkdir without parent = %v, want ErrNotExist", err)
+	}
+	if err := s.MkdirAll("/d/e/f", 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := WriteFile(s, "/d/a.txt", []byte("a"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := WriteFile(s, "/d/b.txt", []byte("b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(filepath.Join(root, "d", "a.txt")); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("created file = %v, %v; want mode 0600", fi, err)
+	}
+
+	// 대상이 있으면 덮어쓴다 (posix-rename)
+	if err := s.Rename("/d/a.txt", "/d/b.txt"); err != nil {
+		t.Fatalf("Rename over file: %v", err)
+	}
+	if b, err := ReadFile(s, "/d/b.txt"); err != nil || string(b) != "a" {
+		t.Errorf("after Rename = %q, %v", b, err)
+	}
+	if err := s.Rename("/d/b.txt", "/d/e/c.txt"); err != nil {
+		t.Fatalf("Rename into dir: %v", err)
+	}
+	if Exists(s, "/d/b.txt") || !Exists(s, "/d/e/c.txt") {
+		t.Error("Rename did not move the file")
+	}
+	var le *os.LinkError
+	if err := s.Rename("/nope", "/x"); !errors.As(err, &le) || le.Old != "/nope" || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Rename missing = %v, want LinkError with ErrNotExist", err)
+	}
+
+	ents, err := s.ReadDir("/d")
+	if err != nil || len(ents) != 1 || ents[0].Name() != "e" {
+		t.Errorf("ReadDir = %v, %v", ents, err)
+	}
+
+	// 루트는 지우지 않는다
+	for _, name := range []string{"/", ""} {
+		if err := s.RemoveAll(name); !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("RemoveAll(%q) = %v, want ErrPermission", name, err)
+		}
+	}
+	if !Exists(s, "/d/e/c.txt") {
+		t.Fatal("RemoveAll on the root removed files")
+	}
+	if err := s.RemoveAll("/d"); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "d")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("RemoveAll left %v", err)
+	}
+	if err := s.RemoveAll("/d"); err != nil {
+		t.Errorf("RemoveAll missing = %v", err)
+	}
+}
+
+// 연결에 실패해도 SSH 에이전트 연결을 닫는다
+func TestDialSFTPClosesAgent(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer func() { _ = l.Close() }()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	// 닫힌 포트
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := dead.Addr().String()
+	_ = dead.Close()
+
+	_, err = DialSFTP(context.Background(), "sftp://tester@"+addr+"/", SFTPOptions{
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		KeyFiles:        []string{filepath.Join(t.TempDir(), "none")},
+		Timeout:         time.Second,
+	})
+	if err == nil {
+		t.Fatal("DialSFTP to a closed port succeeded")
+	}
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err = conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("agent conn read = %v, want EOF (closed)", err)
+	}
+}
+
+// 받기만 하고 아무 말도 하지 않는 서버에는 ctx가 끝나면 바로 돌아온다
+func TestDialSFTPCanceled(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = DialSFTP(ctx, "sftp://tester@"+l.Addr().String()+"/", SFTPOptions{
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		KeyFiles:        []string{filepath.Join(t.TempDir(), "none")},
+		Timeout:         time.Minute,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("DialSFTP took %s after cancel", d)
+	}
+}
+
+// gateConn hang을 닫으면 release까지 쓰기를 막는다 (응답하지 않는 서버)
+type gateConn struct {
+	net.Conn
+	hang, release chan struct{}
+}
+
+func (c *gateConn) Write(p []byte) (int, error) {
+	select {
+	case <-c.hang:
+		<-c.release
+		return 0, net.ErrClosed
+	default:
+	}
+	return c.Conn.Write(p)
+}
+
+// 멈춘 요청은 ctx가 끝나면 돌아오고, 끝내 응답이 없으면 연결을 닫는다
+func TestSFTPContext(t *testing.T) {
+	defer func(d time.Duration) { sftpHangGrace = d }(sftpHangGrace)
+	sftpHangGrace = 50 * time.Millisecond
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cli, srv := net.Pipe()
+	gate := &gateConn{Conn: srv, hang: make(chan struct{}), release: make(chan struct{})}
+	defer close(gate.release)
+	server, err := sftp.NewServer(gate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve() }()
+	client, err := sftp.NewClientPipe(cli, cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSFTP(client, filepath.ToSlash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cs := WithContext(ctx, s)
+	if _, err := cs.Stat("/a.txt"); err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	f, err := cs.Open("/a.txt")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	close(gate.hang)
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := f.Read(make([]byte, 1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Read = %v, want Canceled", err)
+	}
+	if _, err := cs.Stat("/a.txt"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Stat after cancel = %v, want Canceled", err)
+	}
+
+	// 멈춘 연결은 닫혀서 ctx 없는 요청도 기다리지 않는다
+	done := make(chan error, 1)
+	go func() {
+		time.Sleep(2 * sftpHangGrace)
+		_, err := s.Stat("/a.txt")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Stat on a closed connection succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("hung connection was not closed")
+	}
+}
@@ -0,0 +1,165 @@
+// Package vfs 명령과 파일 트리가 쓰는 파일 시스템 추상화.
+// 경로는 filepath 형식의 절대 경로다. 구현: OS(로컬 디스크), Mem(메모리),
+// WebDAV, SFTP, S3(원격 저장소), Archives(zip, tar를 읽기 전용 폴더로), Mux(경로 접두사마다 FS를 붙인다).
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ReadFS 읽기 연산
+type ReadFS interface {
+	Stat(name string) (fs.FileInfo, error)
+	// Lstat 심볼릭 링크를 따라가지 않는다 (링크가 없는 구현은 Stat과 같다)
+	Lstat(name string) (fs.FileInfo, error)
+	// ReadDir 이름순
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Open(name string) (File, error)
+}
+
+// WriteFS 쓰기 연산
+type WriteFS interface {
+	// OpenFile flag는 os.O_* (O_RDONLY, O_WRONLY, O_CREATE, O_TRUNC, O_APPEND, O_EXCL)
+	OpenFile(name string, flag int, perm fs.FileMode) (File, error)
+	Mkdir(name string, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	// Remove 파일 또는 빈 디렉터리
+	Remove(name string) error
+	RemoveAll(name string) error
+	Rename(oldName, newName string) error
+	Chmod(name string, mode fs.FileMode) error
+	// Chtimes zero time은 "바꾸지 않음"
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+type FS interface {
+	ReadFS
+	WriteFS
+}
+
+// localDisk 경로를 로컬 디스크에 두는 FS (OS, 그것을 품은 Mux, Archives)
+type localDisk interface {
+	localPath(name string) (string, bool)
+}
+
+// LocalPath name이 로컬 디스크의 파일이면 그 경로 (fsnotify로 지켜볼 수 있다).
+// 원격 저장소나 아카이브 안은 false
+func LocalPath(fsys ReadFS, name string) (string, bool) {
+	l, ok := fsys.(localDisk)
+	if !ok {
+		return "", false
+	}
+	return l.localPath(name)
+}
+
+// contextual 요청마다 ctx를 받는 FS (원격 저장소, 그것을 품은 Mux, Archives)
+type contextual interface {
+	withContext(ctx context.Context) FS
+}
+
+// WithContext ctx가 끝나면 진행 중인 요청을 멈추는 fsys. 같은 연결과 마운트를 함께 쓴다.
+// ctx를 쓰지 않는 FS(로컬 디스크, Mem)는 그대로 돌려준다
+func WithContext(ctx context.Context, fsys FS) FS {
+	if c, ok := fsys.(contextual); ok && ctx != nil {
+		return c.withContext(ctx)
+	}
+	return fsys
+}
+
+// File 열린 파일. 읽기 또는 쓰기 전용으로 열린 쪽만 동작한다.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+	Stat() (fs.FileInfo, error)
+}
+
+func ReadFile(fsys ReadFS, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f File) {
+		_ = f.Close()
+	}(f)
+	return io.ReadAll(f)
+}
+
+// WriteFile os.WriteFile과 같다
+func WriteFile(fsys WriteFS, name string, data []byte, perm fs.FileMode) error {
+	f, err := fsys.OpenFile(name, writeFlags, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// Exists Lstat이 성공하면 true
+func Exists(fsys ReadFS, name string) bool {
+	_, err := fsys.Lstat(name)
+	return err == nil
+}
+
+// IsDir 링크를 따라가서 디렉터리면 true
+func IsDir(fsys ReadFS, name string) bool {
+	fi, err := fsys.Stat(name)
+	return err == nil && fi.IsDir()
+}
+
+// WalkDir filepath.WalkDir과 같은 순서와 규칙 (fs.SkipDir, fs.SkipAll)
+func WalkDir(fsys ReadFS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func walkDir(fsys ReadFS, path string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if errors.Is(err, fs.SkipDir) && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	ents, err := fsys.ReadDir(path)
+	if err != nil {
+		// 디렉터리를 못 읽으면 한 번 더 알린다 (filepath.WalkDir과 같다)
+		if err = fn(path, d, err); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				err = nil
+			}
+			return err
+		}
+	}
+	for _, e := range ents {
+		if err := walkDir(fsys, filepath.Join(path, e.Name()), e, fn); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// sortEntries 이름순
+func sortEntries(ents []fs.DirEntry) {
+	sort.Slice(ents, func(i, j int) bool { return ents[i].Name() < ents[j].Name() })
+}
@@ -0,0 +1,499 @@
+package vfs
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// WebDAV WebDAV 서버의 한 경로를 루트로 보는 FS (PROPFIND, GET, PUT, MKCOL, DELETE, MOVE).
+// 권한과 시각은 서버가 정한다: Chmod는 지원하지 않고 Chtimes는 존재만 확인한다.
+type WebDAV struct {
+	base     *url.URL // 끝의 "/"는 없앤다
+	username string
+	password string
+	client   *http.Client
+	ctx      context.Context // WithContext로 만든 것만
+}
+
+type WebDAVOptions struct {
+	Username string
+	Password string
+	Client   *http.Client // 없으면 defaultDAVClient
+}
+
+// davTimeout 연결과 응답 헤더를 기다리는 한도. 큰 파일의 본문은 시간이 걸려도 되므로
+// Client.Timeout 대신 Transport에 건다 (본문은 명령의 ctx로 멈춘다)
+const davTimeout = 30 * time.Second
+
+var defaultDAVClient = &http.Client{Transport: newDAVTransport()}
+
+func newDAVTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = (&net.Dialer{Timeout: davTimeout, KeepAlive: 30 * time.Second}).DialContext
+	t.TLSHandshakeTimeout = davTimeout
+	t.ResponseHeaderTimeout = davTimeout
+	return t
+}
+
+// NewWebDAV rawURL은 http(s)://host/path. 연결은 첫 요청에서 맺는다.
+func NewWebDAV(rawURL string, opts WebDAVOptions) (*WebDAV, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("webdav: unsupported url %q", rawURL)
+	}
+	// URL 속 사용자 정보는 옵션보다 우선하지 않는다
+	if u.User != nil && opts.Username == "" {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	client := opts.Client
+	if client == nil {
+		client = defaultDAVClient
+	}
+	return &WebDAV{base: u, username: opts.Username, password: opts.Password, client: client}, nil
+}
+
+func (w *WebDAV) String() string { return w.base.String() }
+
+func (w *WebDAV) withContext(ctx context.Context) FS {
+	cp := *w
+	cp.ctx = ctx
+	return &cp
+}
+
+func (w *WebDAV) url(name string) string {
+	u := *w.base
+	u.Path = path.Join("/", w.base.Path, filepath.ToSlash(name))
+	return u.String()
+}
+
+func (w *WebDAV) do(method, name string, body io.Reader, header http.Header) (*http.Response, error) {
+	ctx := w.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.url(name), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	return w.client.Do(req)
+}
+
+// discard 본문을 비우고 닫는다 (연결 재사용)
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// statusErr 실패한 응답을 fs 오류로
+func statusErr(op, name string, resp *http.Response) error {
+	return &fs.PathError{Op: op, Path: name, Err: statusCause(resp)}
+}
+
+func statusCause(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusConflict: // 409: 부모 디렉터리가 없음
+		return fs.ErrNotExist
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fs.ErrPermission
+	case http.StatusPreconditionFailed, http.StatusMethodNotAllowed:
+		return fs.ErrExist
+	case http.StatusInsufficientStorage:
+		return syscall.ENOSPC
+	}
+	return errors.New(resp.Status)
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/><D:getcontentlength/><D:getlastmodified/></D:prop></D:propfind>`
+
+type davMultistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href     string `xml:"DAV: href"`
+	Propstat []struct {
+		Prop struct {
+			ResourceType struct {
+				Collection *struct{} `xml:"DAV: collection"`
+			} `xml:"DAV: resourcetype"`
+			Length   string `xml:"DAV: getcontentlength"`
+			Modified string `xml:"DAV: getlastmodified"`
+		} `xml:"DAV: prop"`
+	} `xml:"DAV: propstat"`
+}
+
+// path href의 경로 부분 (디코딩, 끝의 "/" 제거)
+func (r davResponse) path() string {
+	p := r.Href
+	if u, err := url.Parse(r.Href); err == nil {
+		p = u.Path
+	}
+	if p != "/" {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+func (r davResponse) info(name string) fs.FileInfo {
+	fi := memInfo{name: name, mode: 0o644}
+	for _, ps := range r.Propstat {
+		if ps.Prop.ResourceType.Collection != nil {
+			fi.mode = fs.ModeDir | 0o755
+		}
+		if n, err := strconv.ParseInt(ps.Prop.Length, 10, 64); err == nil {
+			fi.size = n
+		}
+		if t, err := http.ParseTime(ps.Prop.Modified); err == nil {
+			fi.modTime = t
+		}
+	}
+	if fi.mode.IsDir() {
+		fi.size = 0
+	}
+	return fi
+}
+
+func (w *WebDAV) propfind(op, name, depth string) ([]davResponse, error) {
+	resp, err := w.do("PROPFIND", name, strings.NewReader(propfindBody), http.Header{
+		"Depth":        {depth},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	})
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	defer discard(resp)
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, statusErr(op, name, resp)
+	}
+
+	var ms davMultistatus
+	if err = xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return ms.Responses, nil
+}
+
+func (w *WebDAV) Stat(name string) (fs.FileInfo, error) {
+	rs, err := w.propfind("stat", name, "0")
+	if err != nil {
+		return nil, err
+	}
+	if len(rs) == 0 {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return rs[0].info(filepath.Base(name)), nil
+}
+
+func (w *WebDAV) Lstat(name string) (fs.FileInfo, error) {
+	return w.Stat(name)
+}
+
+func (w *WebDAV) ReadDir(name string) ([]fs.DirEntry, error) {
+	rs, err := w.propfind("readdir", name, "1")
+	if err != nil {
+		return nil, err
+	}
+
+	self, _ := url.Parse(w.url(name))
+	selfPath := strings.TrimSuffix(self.Path, "/")
+	var ents []fs.DirEntry
+	for _, r := range rs {
+		p := r.path()
+		if p == selfPath || p == self.Path {
+			if !r.info("").IsDir() {
+				return nil, &fs.PathError{Op: "readdir", Path: name, Err: syscall.ENOTDIR}
+			}
+			continue
+		}
+		ents = append(ents, fs.FileInfoToDirEntry(r.info(path.Base(p))))
+	}
+	sortEntries(ents)
+	return ents, nil
+}
+
+func (w *WebDAV) Open(name string) (File, error) {
+	resp, err := w.do(http.MethodGet, name, nil, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer discard(resp)
+		// 디렉터리 GET은 서버마다 다르다
+		if fi, sErr := w.Stat(name); sErr == nil && fi.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		return nil, statusErr("open", name, resp)
+	}
+	return &davReader{w: w, name: name, body: resp.Body}, nil
+}
+
+// OpenFile 쓰기는 닫을 때까지 PUT 하나로 흘려보낸다. 위치를 옮길 수 없어서
+// O_APPEND가 아니면 처음 Write부터 파일 전체를 새로 쓴다 (O_RDWR는 지원하지 않음).
+func (w *WebDAV) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return w.Open(name)
+	}
+	if flag&os.O_RDWR != 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+	}
+
+	fi, err := w.Stat(name)
+	exists := err == nil
+	switch {
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	case exists && fi.IsDir():
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	case exists && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case !exists && flag&os.O_CREATE == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	f := &davWriter{w: w, name: name, mustPut: !exists || flag&os.O_TRUNC != 0}
+	if exists && flag&os.O_APPEND != 0 && flag&os.O_TRUNC == 0 {
+		// 기존 내용을 앞에 붙여 다시 올린다
+		r, err := w.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		f.prefix = r
+	}
+	return f, nil
+}
+
+func (w *WebDAV) Mkdir(name string, _ fs.FileMode) error {
+	resp, err := w.do("MKCOL", name, nil, nil)
+	if err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	defer discard(resp)
+	if resp.StatusCode != http.StatusCreated {
+		return statusErr("mkdir", name, resp)
+	}
+	return nil
+}
+
+func (w *WebDAV) MkdirAll(name string, perm fs.FileMode) error {
+	fi, err := w.Stat(name)
+	switch {
+	case err == nil && fi.IsDir():
+		return nil
+	case err == nil:
+		return &fs.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	if parent := filepath.Dir(name); parent != name {
+		if err = w.MkdirAll(parent, perm); err != nil {
+			return err
+		}
+	}
+	if err = w.Mkdir(name, perm); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+func (w *WebDAV) Remove(name string) error {
+	fi, err := w.Stat(name)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		// DELETE는 하위까지 지우므로 빈 디렉터리인지 먼저 본다
+		ents, err := w.ReadDir(name)
+		if err != nil {
+			return err
+		}
+		if len(ents) > 0 {
+			return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+	return w.delete("remove", name)
+}
+
+func (w *WebDAV) RemoveAll(name string) error {
+	if filepath.Dir(name) == name {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrPermission}
+	}
+	err := w.delete("removeall", name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (w *WebDAV) delete(op, name string) error {
+	resp, err := w.do(http.MethodDelete, name, nil, nil)
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	defer discard(resp)
+	if resp.StatusCode/100 != 2 {
+		return statusErr(op, name, resp)
+	}
+	return nil
+}
+
+func (w *WebDAV) Rename(oldName, newName string) error {
+	linkErr := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: err}
+	}
+	src, err := w.Stat(oldName)
+	if err != nil {
+		return linkErr(fs.ErrNotExist)
+	}
+	// os.Rename처럼: 파일은 덮어쓰고, 디렉터리는 빈 디렉터리만 바꿀 수 있다
+	if dst, err := w.Stat(newName); err == nil {
+		switch {
+		case dst.IsDir() && !src.IsDir():
+			return linkErr(syscall.EISDIR)
+		case !dst.IsDir() && src.IsDir():
+			return linkErr(syscall.ENOTDIR)
+		case dst.IsDir():
+			if ents, err := w.ReadDir(newName); err != nil || len(ents) > 0 {
+				return linkErr(syscall.ENOTEMPTY)
+			}
+		}
+	}
+
+	resp, err := w.do("MOVE", oldName, nil, http.Header{
+		"Destination": {w.url(newName)},
+		"Overwrite":   {"T"},
+	})
+	if err != nil {
+		return linkErr(err)
+	}
+	defer discard(resp)
+	if resp.StatusCode/100 != 2 {
+		return linkErr(statusCause(resp))
+	}
+	return nil
+}
+
+func (w *WebDAV) Chmod(name string, _ fs.FileMode) error {
+	return &fs.PathError{Op: "chmod", Path: name, Err: errors.ErrUnsupported}
+}
+
+// Chtimes 수정 시각은 서버가 정한다. 있는지만 확인한다.
+func (w *WebDAV) Chtimes(name string, _, _ time.Time) error {
+	_, err := w.Stat(name)
+	return err
+}
+
+// davReader GET 응답 본문
+type davReader struct {
+	w    *WebDAV
+	name string
+	body io.ReadCloser
+}
+
+func (f *davReader) Read(p []byte) (int, error) { return f.body.Read(p) }
+
+func (f *davReader) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+}
+
+func (f *davReader) Close() error { return f.body.Close() }
+
+func (f *davReader) Stat() (fs.FileInfo, error) { return f.w.Stat(f.name) }
+
+// davWriter 첫 Write에서 PUT을 시작하고 Close에서 응답을 기다린다
+type davWriter struct {
+	w       *WebDAV
+	name    string
+	prefix  File // O_APPEND: 기존 내용
+	mustPut bool // 쓴 게 없어도 닫을 때 올린다 (새 파일, O_TRUNC)
+
+	pw     *io.PipeWriter
+	done   chan error
+	closed bool
+}
+
+func (f *davWriter) start() {
+	pr, pw := io.Pipe()
+	f.pw, f.done = pw, make(chan error, 1)
+
+	var body io.Reader = pr
+	if f.prefix != nil {
+		body = io.MultiReader(f.prefix, pr)
+	}
+	go func() {
+		resp, err := f.w.do(http.MethodPut, f.name, body, nil)
+		if err == nil {
+			if resp.StatusCode/100 != 2 {
+				err = statusErr("write", f.name, resp)
+			}
+			discard(resp)
+		} else {
+			err = &fs.PathError{Op: "write", Path: f.name, Err: err}
+		}
+		// 실패하면 쓰던 쪽도 같은 오류로 멈춘다
+		_ = pr.CloseWithError(err)
+		f.done <- err
+	}()
+}
+
+func (f *davWriter) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrPermission}
+}
+
+func (f *davWriter) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.pw == nil {
+		f.start()
+	}
+	return f.pw.Write(p)
+}
+
+func (f *davWriter) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	if f.prefix != nil {
+		defer func() { _ = f.prefix.Close() }()
+	}
+	if f.pw == nil {
+		if !f.mustPut {
+			return nil
+		}
+		f.start()
+	}
+	_ = f.pw.Close()
+	return <-f.done
+}
+
+func (f *davWriter) Stat() (fs.FileInfo, error) { return f.w.Stat(f.name) }
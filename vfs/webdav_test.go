@@ -0,0 +1,137 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"golang.org/x/net/webdav"
+)
+
+func newTestWebDAV(t *testing.T) *WebDAV {
+	t.Helper()
+	srv := httptest.NewServer(&webdav.Handler{
+		Prefix:     "/dav",
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	})
+	t.Cleanup(srv.Close)
+
+	w, err := NewWebDAV(srv.URL+"/dav/", WebDAVOptions{Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestWebDAVRoundTrip(t *testing.T) {
+	w := newTestWebDAV(t)
+
+	if err := w.MkdirAll("/a/b", 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := w.Mkdir("/a", 0o755); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkdir existing = %v, want ErrExist", err)
+	}
+	if err := w.Mkdir("/none/x", 0o755); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Mkdir without parent = %v, want ErrNotExist", err)
+	}
+	if err := WriteFile(w, "/a/b/f.txt", []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	fi, err := w.Stat("/a/b/f.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.IsDir() || fi.Size() != 5 || fi.Name() != "f.txt" {
+		t.Errorf("Stat = %s dir=%v size=%d", fi.Name(), fi.IsDir(), fi.Size())
+	}
+	if fi, err = w.Stat("/a"); err != nil || !fi.IsDir() {
+		t.Errorf("Stat dir = %v, %v", fi, err)
+	}
+	if _, err = w.Stat("/nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat missing = %v, want ErrNotExist", err)
+	}
+
+	// 덧붙이기는 기존 내용을 앞에 두고 다시 올린다
+	f, err := w.OpenFile("/a/b/f.txt", os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile append: %v", err)
+	}
+	if _, err = f.Write([]byte(" world")); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if b, err := ReadFile(w, "/a/b/f.txt"); err != nil || string(b) != "hello world" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if _, err = w.OpenFile("/a/b/f.txt", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("OpenFile O_EXCL = %v, want ErrExist", err)
+	}
+	if _, err = w.Open("/a"); !errors.Is(err, syscall.EISDIR) {
+		t.Errorf("Open dir = %v, want EISDIR", err)
+	}
+
+	ents, err := w.ReadDir("/a/b")
+	if err != nil || len(ents) != 1 || ents[0].Name() != "f.txt" {
+		t.Errorf("ReadDir = %v, %v", ents, err)
+	}
+	if _, err = w.ReadDir("/a/b/f.txt"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("ReadDir file = %v, want ENOTDIR", err)
+	}
+
+	if err = w.Rename("/a/b/f.txt", "/a/g.txt"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if Exists(w, "/a/b/f.txt") || !Exists(w, "/a/g.txt") {
+		t.Error("Rename did not move the file")
+	}
+
+	if err = w.Remove("/a"); !errors.Is(err, syscall.ENOTEMPTY) {
+		t.Errorf("Remove non-empty = %v, want ENOTEMPTY", err)
+	}
+	if err = w.RemoveAll("/a"); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if Exists(w, "/a") {
+		t.Error("RemoveAll left /a")
+	}
+	if err = w.RemoveAll("/a"); err != nil {
+		t.Errorf("RemoveAll missing = %v", err)
+	}
+	if err = w.RemoveAll("/"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("RemoveAll root = %v, want ErrPermission", err)
+	}
+}
+
+func TestWebDAVContext(t *testing.T) {
+	w := newTestWebDAV(t)
+	if err := w.Mkdir("/d", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WithContext(ctx, w).Stat("/d"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Stat with canceled ctx = %v, want context.Canceled", err)
+	}
+	// Mux와 Archives도 마운트된 FS에 ctx를 넘긴다
+	mux := NewMux(NewMem())
+	if err := mux.Mount("/@dav", NewArchives(w)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WithContext(ctx, mux).Stat("/@dav/d"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Mux Stat with canceled ctx = %v, want context.Canceled", err)
+	}
+	// 원래 FS는 그대로 동작한다
+	if _, err := mux.Stat("/@dav/d"); err != nil {
+		t.Errorf("Mux Stat = %v", err)
+	}
+}